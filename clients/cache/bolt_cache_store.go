@@ -0,0 +1,173 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/file"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	boltFileName = "nacos-cache.db"
+	// the database file is locked by the process which opens it, so the other processes fail after the timeout
+	boltOpenTimeout = time.Second
+)
+
+var boltBucket = []byte("nacos-cache")
+
+// The database file is opened once in a process, the clients with the same cache directory share it
+type sharedBoltDB struct {
+	db   *bolt.DB
+	refs int
+}
+
+var (
+	boltMutex sync.Mutex
+	boltDBs   = map[string]*sharedBoltDB{}
+)
+
+type boltCacheStore struct {
+	path      string
+	db        *bolt.DB
+	closeOnce sync.Once
+}
+
+// NewBoltCacheStore creates the store which keeps all the keys in the bbolt database file nacos-cache.db in dir,
+// the file can only be opened by one process at a time
+func NewBoltCacheStore(dir string) (CacheStore, error) {
+	if err := file.MkdirIfNecessary(dir); err != nil {
+		return nil, err
+	}
+	path, err := filepath.Abs(filepath.Join(dir, boltFileName))
+	if err != nil {
+		return nil, err
+	}
+	boltMutex.Lock()
+	defer boltMutex.Unlock()
+	shared, ok := boltDBs[path]
+	if !ok {
+		db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: boltOpenTimeout})
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("failed to open bolt cache:%s,err:%+v", path, err))
+		}
+		err = db.Update(func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(boltBucket)
+			return err
+		})
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		shared = &sharedBoltDB{db: db}
+		boltDBs[path] = shared
+	}
+	shared.refs++
+	return &boltCacheStore{path: path, db: shared.db}, nil
+}
+
+func (s *boltCacheStore) Get(key string) (content string, err error) {
+	err = s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(boltBucket).Get([]byte(key))
+		if value == nil {
+			return ErrCacheNotFound
+		}
+		// the value is only valid in the transaction, parseCacheFile copies it
+		content, _, err = parseCacheFile(string(value))
+		return err
+	})
+	return content, err
+}
+
+func (s *boltCacheStore) Put(key string, content string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), []byte(formatCacheContent(content)))
+	})
+}
+
+func (s *boltCacheStore) Delete(key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(key))
+	})
+}
+
+func (s *boltCacheStore) List() ([]string, error) {
+	var keys []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).ForEach(func(k, _ []byte) error {
+			keys = append(keys, string(k))
+			return nil
+		})
+	})
+	return keys, err
+}
+
+func (s *boltCacheStore) Prune(maxAge time.Duration) int {
+	deadline := time.Now().Add(-maxAge)
+	removed := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		var staleKeys [][]byte
+		err := bucket.ForEach(func(k, v []byte) error {
+			_, writeTime, err := parseCacheFile(string(v))
+			// the corrupted values can never be read, so they are removed as well
+			if err == errCorruptedCache || err == nil && writeTime.Before(deadline) {
+				staleKeys = append(staleKeys, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range staleKeys {
+			if err = bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		removed = len(staleKeys)
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("failed to prune bolt cache:%s,err:%+v", s.path, err)
+		return 0
+	}
+	if removed > 0 {
+		logger.Infof("pruned %d stale cache entries in %s", removed, s.path)
+	}
+	return removed
+}
+
+// Close closes the database file when all the stores of it are closed
+func (s *boltCacheStore) Close() (err error) {
+	s.closeOnce.Do(func() {
+		boltMutex.Lock()
+		defer boltMutex.Unlock()
+		shared := boltDBs[s.path]
+		shared.refs--
+		if shared.refs == 0 {
+			delete(boltDBs, s.path)
+			err = shared.db.Close()
+		}
+	})
+	return err
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/constant"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/file"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
+)
+
+// CacheStore is the storage of the config snapshot or the service cache, the keys are the cache keys
+// built by util.GetConfigCacheKey and util.GetServiceCacheKey
+type CacheStore interface {
+	// Get returns the content of key, ErrCacheNotFound is returned if key is not in the store
+	Get(key string) (string, error)
+
+	// Put adds or replaces the content of key
+	Put(key string, content string) error
+
+	// Delete removes key, it does nothing if key is not in the store
+	Delete(key string) error
+
+	// List returns all the keys in the store
+	List() ([]string, error)
+
+	// Prune removes the keys written more than maxAge ago, it returns the count of the removed keys
+	Prune(maxAge time.Duration) int
+
+	// Close releases the resources of the store, the store can not be used after closed
+	Close() error
+}
+
+var ErrCacheNotFound = errors.New("cache not found")
+
+// NewCacheStore creates the store of storeType in dir, the file store is created if storeType is empty
+func NewCacheStore(storeType constant.CacheStoreType, dir string) (CacheStore, error) {
+	switch storeType {
+	case "", constant.CACHE_STORE_FILE:
+		return NewFileCacheStore(dir), nil
+	case constant.CACHE_STORE_MEMORY:
+		return NewMemoryCacheStore(), nil
+	case constant.CACHE_STORE_BOLT:
+		return NewBoltCacheStore(dir)
+	default:
+		return nil, errors.New("unknown cache store type:" + string(storeType))
+	}
+}
+
+// WriteServiceToStore writes the service as json to the store
+func WriteServiceToStore(store CacheStore, service model.Service) {
+	sb, _ := json.Marshal(service)
+	cacheKey := util.GetServiceCacheKey(service.Name, service.Clusters)
+	if err := store.Put(cacheKey, string(sb)); err != nil {
+		logger.Errorf("failed to write name cache:%s ,value:%s ,err:%+v", cacheKey, string(sb), err)
+	}
+}
+
+// ReadServicesFromStore reads all the services in the store, the keys of the map are the service cache keys
+func ReadServicesFromStore(store CacheStore) map[string]model.Service {
+	keys, err := store.List()
+	if err != nil {
+		logger.Errorf("list name cache failed!err:%+v", err)
+		return nil
+	}
+	serviceMap := map[string]model.Service{}
+	for _, key := range keys {
+		s, err := store.Get(key)
+		if err != nil {
+			logger.Errorf("failed to read name cache:%s,err:%+v ", key, err)
+			continue
+		}
+
+		service := util.JsonToService(s)
+
+		if service == nil {
+			continue
+		}
+
+		serviceMap[key] = *service
+	}
+
+	logger.Info("finish loading name cache, total: " + strconv.Itoa(len(keys)))
+	return serviceMap
+}
+
+type fileCacheStore struct {
+	dir string
+}
+
+// NewFileCacheStore creates the store which writes a cache file for each key in dir
+func NewFileCacheStore(dir string) CacheStore {
+	return &fileCacheStore{dir: dir}
+}
+
+func (s *fileCacheStore) Get(key string) (string, error) {
+	content, _, err := readCacheFile(GetFileName(key, s.dir))
+	if os.IsNotExist(err) {
+		return "", ErrCacheNotFound
+	}
+	return content, err
+}
+
+func (s *fileCacheStore) Put(key string, content string) error {
+	file.MkdirIfNecessary(s.dir)
+	return writeCacheFile(GetFileName(key, s.dir), content)
+}
+
+func (s *fileCacheStore) Delete(key string) error {
+	err := os.Remove(GetFileName(key, s.dir))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *fileCacheStore) List() ([]string, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(files))
+	for _, f := range files {
+		// skip the quarantine directory and the temp files
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		key := f.Name()
+		if runtime.GOOS == constant.OS_WINDOWS {
+			key = strings.ReplaceAll(key, constant.WINDOWS_LEGAL_NAME_SPLITER, ":")
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (s *fileCacheStore) Prune(maxAge time.Duration) int {
+	return PruneCacheFiles(s.dir, maxAge)
+}
+
+func (s *fileCacheStore) Close() error {
+	return nil
+}
+
+type memoryCacheEntry struct {
+	content   string
+	writeTime time.Time
+}
+
+type memoryCacheStore struct {
+	entries ConcurrentMap
+}
+
+// NewMemoryCacheStore creates the store which keeps everything in memory, it's used for the tests
+// and the read-only containers
+func NewMemoryCacheStore() CacheStore {
+	return &memoryCacheStore{entries: NewConcurrentMap()}
+}
+
+func (s *memoryCacheStore) Get(key string) (string, error) {
+	entry, ok := s.entries.Get(key)
+	if !ok {
+		return "", ErrCacheNotFound
+	}
+	return entry.(memoryCacheEntry).content, nil
+}
+
+func (s *memoryCacheStore) Put(key string, content string) error {
+	s.entries.Set(key, memoryCacheEntry{content: content, writeTime: time.Now()})
+	return nil
+}
+
+func (s *memoryCacheStore) Delete(key string) error {
+	s.entries.Remove(key)
+	return nil
+}
+
+func (s *memoryCacheStore) List() ([]string, error) {
+	return s.entries.Keys(), nil
+}
+
+func (s *memoryCacheStore) Prune(maxAge time.Duration) int {
+	deadline := time.Now().Add(-maxAge)
+	removed := 0
+	for key, entry := range s.entries.Items() {
+		if entry.(memoryCacheEntry).writeTime.Before(deadline) {
+			s.entries.Remove(key)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (s *memoryCacheStore) Close() error {
+	return nil
+}
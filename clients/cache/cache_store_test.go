@@ -0,0 +1,95 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/constant"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+)
+
+func TestCacheStores(t *testing.T) {
+	for _, storeType := range []constant.CacheStoreType{constant.CACHE_STORE_FILE, constant.CACHE_STORE_MEMORY, constant.CACHE_STORE_BOLT} {
+		t.Run(string(storeType), func(t *testing.T) {
+			store, err := NewCacheStore(storeType, t.TempDir())
+			assert.Nil(t, err)
+			defer store.Close()
+
+			_, err = store.Get("dataId@@group@@")
+			assert.Equal(t, ErrCacheNotFound, err)
+			assert.Nil(t, store.Put("dataId@@group@@", "a=1"))
+			assert.Nil(t, store.Put("dataId@@group@@", "a=2"))
+			assert.Nil(t, store.Put("empty@@group@@", ""))
+			content, err := store.Get("dataId@@group@@")
+			assert.Nil(t, err)
+			assert.Equal(t, "a=2", content)
+			content, err = store.Get("empty@@group@@")
+			assert.Nil(t, err)
+			assert.Equal(t, "", content)
+
+			keys, err := store.List()
+			assert.Nil(t, err)
+			sort.Strings(keys)
+			assert.Equal(t, []string{"dataId@@group@@", "empty@@group@@"}, keys)
+
+			assert.Nil(t, store.Delete("empty@@group@@"))
+			assert.Nil(t, store.Delete("empty@@group@@"))
+			keys, _ = store.List()
+			assert.Equal(t, []string{"dataId@@group@@"}, keys)
+
+			assert.Equal(t, 0, store.Prune(time.Hour))
+			time.Sleep(10 * time.Millisecond)
+			assert.Equal(t, 1, store.Prune(5*time.Millisecond))
+			keys, _ = store.List()
+			assert.Empty(t, keys)
+		})
+	}
+}
+
+func TestNewCacheStoreWithUnknownType(t *testing.T) {
+	_, err := NewCacheStore("redis", t.TempDir())
+	assert.NotNil(t, err)
+}
+
+func TestBoltCacheStoreReopen(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewBoltCacheStore(dir)
+	assert.Nil(t, err)
+	// the stores of the same directory share the database file
+	other, err := NewBoltCacheStore(dir)
+	assert.Nil(t, err)
+	WriteServiceToStore(store, model.Service{Name: "DEFAULT_GROUP@@demo", Clusters: "a"})
+	assert.Nil(t, other.Close())
+	assert.Nil(t, other.Close())
+	assert.FileExists(t, filepath.Join(dir, boltFileName))
+
+	services := ReadServicesFromStore(store)
+	assert.Nil(t, store.Close())
+	assert.Equal(t, "DEFAULT_GROUP@@demo", services["DEFAULT_GROUP@@demo@@a"].Name)
+
+	store, err = NewBoltCacheStore(dir)
+	assert.Nil(t, err)
+	defer store.Close()
+	services = ReadServicesFromStore(store)
+	assert.Equal(t, 1, len(services))
+	assert.Equal(t, "a", services["DEFAULT_GROUP@@demo@@a"].Clusters)
+}
@@ -17,13 +17,14 @@
 package cache
 
 import (
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-errors/errors"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/constant"
@@ -42,61 +43,190 @@ func GetFileName(cacheKey string, cacheDir string) string {
 	return cacheDir + string(os.PathSeparator) + cacheKey
 }
 
+// The cache files are written by the temp file and rename, and start with the header line
+// "#nacos-cache v<version> md5=<md5 of the content> ts=<write time in milliseconds>".
+// The files without the header are read as plain content, such as the files written by the old
+// versions. The failover files are written by hand, so they are never checksummed or quarantined.
+const (
+	cacheFileMagic    = "#nacos-cache"
+	cacheFileVersion  = 1
+	quarantineDirName = ".quarantine"
+	tempFileSuffix    = ".tmp"
+)
+
+var errCorruptedCache = errors.New("corrupted cache file")
+
 func WriteServicesToFile(service model.Service, cacheDir string) {
+	WriteServiceToStore(NewFileCacheStore(cacheDir), service)
+}
+
+func ReadServicesFromFile(cacheDir string) map[string]model.Service {
+	return ReadServicesFromStore(NewFileCacheStore(cacheDir))
+}
+
+func WriteConfigToFile(cacheKey string, cacheDir string, content string) {
 	file.MkdirIfNecessary(cacheDir)
-	sb, _ := json.Marshal(service)
-	domFileName := GetFileName(util.GetServiceCacheKey(service.Name, service.Clusters), cacheDir)
+	fileName := GetFileName(cacheKey, cacheDir)
+	err := writeCacheFile(fileName, content)
+	if err != nil {
+		logger.Errorf("failed to write config  cache:%s ,value:%s ,err:%+v", fileName, content, err)
+	}
+}
 
-	err := ioutil.WriteFile(domFileName, sb, 0666)
+func ReadConfigFromFile(cacheKey string, cacheDir string) (string, error) {
+	fileName := GetFileName(cacheKey, cacheDir)
+	content, _, err := readCacheFile(fileName)
 	if err != nil {
-		logger.Errorf("failed to write name cache:%s ,value:%s ,err:%+v", domFileName, string(sb), err)
+		return "", errors.New(fmt.Sprintf("failed to read config cache file:%s,err:%+v ", fileName, err))
 	}
+	return content, nil
+}
 
+// ReadFailoverConfig reads the failover file as plain content, the header line is dropped
+// without checking the md5 if the file is copied from the snapshot and edited by hand
+func ReadFailoverConfig(cacheKey string, failoverDir string) (string, error) {
+	fileName := GetFileName(cacheKey, failoverDir)
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("failed to read failover file:%s,err:%+v ", fileName, err))
+	}
+	content := string(b)
+	if strings.HasPrefix(content, cacheFileMagic+" ") {
+		index := strings.Index(content, "\n")
+		content = content[index+1:]
+	}
+	return content, nil
 }
 
-func ReadServicesFromFile(cacheDir string) map[string]model.Service {
+// PruneCacheFiles removes the cache files written more than maxAge ago, as well as the quarantined
+// and the temp files modified more than maxAge ago, it returns the count of the removed files
+func PruneCacheFiles(cacheDir string, maxAge time.Duration) int {
+	deadline := time.Now().Add(-maxAge)
+	removed := 0
 	files, err := ioutil.ReadDir(cacheDir)
 	if err != nil {
-		logger.Errorf("read cacheDir:%s failed!err:%+v", cacheDir, err)
-		return nil
+		return 0
 	}
-	serviceMap := map[string]model.Service{}
 	for _, f := range files {
-		fileName := GetFileName(f.Name(), cacheDir)
-		b, err := ioutil.ReadFile(fileName)
-		if err != nil {
-			logger.Errorf("failed to read name cache file:%s,err:%+v ", fileName, err)
+		fileName := cacheDir + string(os.PathSeparator) + f.Name()
+		if f.IsDir() {
+			if f.Name() == quarantineDirName {
+				removed += PruneCacheFiles(fileName, maxAge)
+			}
 			continue
 		}
-
-		s := string(b)
-		service := util.JsonToService(s)
-
-		if service == nil {
-			continue
+		writeTime := f.ModTime()
+		if !strings.HasPrefix(f.Name(), ".") {
+			var readErr error
+			if _, writeTime, readErr = readCacheFile(fileName); readErr != nil {
+				// the corrupted file is quarantined by readCacheFile
+				continue
+			}
+		}
+		if writeTime.Before(deadline) {
+			if err = os.Remove(fileName); err != nil {
+				logger.Errorf("failed to remove stale cache file:%s,err:%+v", fileName, err)
+				continue
+			}
+			removed++
 		}
-
-		serviceMap[f.Name()] = *service
 	}
-
-	logger.Info("finish loading name cache, total: " + strconv.Itoa(len(files)))
-	return serviceMap
+	if removed > 0 {
+		logger.Infof("pruned %d stale cache files in %s", removed, cacheDir)
+	}
+	return removed
 }
 
-func WriteConfigToFile(cacheKey string, cacheDir string, content string) {
-	file.MkdirIfNecessary(cacheDir)
-	fileName := GetFileName(cacheKey, cacheDir)
-	err := ioutil.WriteFile(fileName, []byte(content), 0666)
+// Write the content with the header to a temp file in the same directory, then rename it to fileName,
+// so that fileName is either the old content or the new one if the process crashes during the write
+func writeCacheFile(fileName string, content string) error {
+	dir, base := filepath.Split(fileName)
+	if len(dir) == 0 {
+		dir = "."
+	}
+	tempFile, err := ioutil.TempFile(dir, "."+base+".*"+tempFileSuffix)
 	if err != nil {
-		logger.Errorf("failed to write config  cache:%s ,value:%s ,err:%+v", fileName, content, err)
+		return err
+	}
+	tempName := tempFile.Name()
+	_, err = tempFile.WriteString(formatCacheContent(content))
+	if err == nil {
+		err = tempFile.Sync()
+	}
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
 	}
+	if err == nil {
+		err = os.Chmod(tempName, 0644)
+	}
+	if err == nil {
+		err = os.Rename(tempName, fileName)
+	}
+	if err != nil {
+		os.Remove(tempName)
+	}
+	return err
 }
 
-func ReadConfigFromFile(cacheKey string, cacheDir string) (string, error) {
-	fileName := GetFileName(cacheKey, cacheDir)
+// Add the header line to the content, the bolt store keeps the values in the same format as the files
+func formatCacheContent(content string) string {
+	return fmt.Sprintf("%s v%d md5=%s ts=%d\n", cacheFileMagic, cacheFileVersion, util.Md5(content), util.CurrentMillis()) + content
+}
+
+// Read the content of the cache file and its write time, the corrupted file is moved to the quarantine directory
+func readCacheFile(fileName string) (content string, writeTime time.Time, err error) {
 	b, err := ioutil.ReadFile(fileName)
 	if err != nil {
-		return "", errors.New(fmt.Sprintf("failed to read config cache file:%s,err:%+v ", fileName, err))
+		return "", time.Time{}, err
+	}
+	content = string(b)
+	if !strings.HasPrefix(content, cacheFileMagic+" ") {
+		info, err := os.Stat(fileName)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		return content, info.ModTime(), nil
+	}
+	content, writeTime, err = parseCacheFile(content)
+	if err == errCorruptedCache {
+		quarantineCacheFile(fileName)
+	}
+	return content, writeTime, err
+}
+
+func parseCacheFile(data string) (content string, writeTime time.Time, err error) {
+	index := strings.Index(data, "\n")
+	if index < 0 {
+		return "", time.Time{}, errCorruptedCache
+	}
+	var (
+		version int
+		md5Str  string
+		ts      int64
+	)
+	header := data[:index]
+	if _, err = fmt.Sscanf(header, cacheFileMagic+" v%d md5=%s ts=%d", &version, &md5Str, &ts); err != nil {
+		return "", time.Time{}, errCorruptedCache
+	}
+	if version > cacheFileVersion {
+		return "", time.Time{}, errors.New(fmt.Sprintf("unsupported cache file version:%d", version))
+	}
+	content = data[index+1:]
+	if util.Md5(content) != md5Str {
+		return "", time.Time{}, errCorruptedCache
+	}
+	return content, time.Unix(0, ts*int64(time.Millisecond)), nil
+}
+
+// Move the corrupted file to the quarantine directory, where it is kept for investigation until pruned
+func quarantineCacheFile(fileName string) {
+	dir, base := filepath.Split(fileName)
+	quarantineDir := filepath.Join(dir, quarantineDirName)
+	file.MkdirIfNecessary(quarantineDir)
+	target := filepath.Join(quarantineDir, base+"."+strconv.FormatInt(util.CurrentMillis(), 10))
+	if err := os.Rename(fileName, target); err != nil {
+		logger.Errorf("failed to quarantine corrupted cache file:%s,err:%+v", fileName, err)
+		return
 	}
-	return string(b), nil
+	logger.Warnf("corrupted cache file:%s is quarantined to %s", fileName, target)
 }
@@ -17,11 +17,17 @@
 package cache
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/constant"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
 )
 
 func TestGetFileName(t *testing.T) {
@@ -34,3 +40,92 @@ func TestGetFileName(t *testing.T) {
 		assert.Equal(t, name, "tmp/nacos@@providers:org.apache.dubbo.UserProvider:hangzhou")
 	}
 }
+
+func TestWriteAndReadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	WriteConfigToFile("dataId@@group@@", dir, "a=1\nb=2")
+	b, err := ioutil.ReadFile(GetFileName("dataId@@group@@", dir))
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(b), "#nacos-cache v1 md5="))
+
+	content, err := ReadConfigFromFile("dataId@@group@@", dir)
+	assert.Nil(t, err)
+	assert.Equal(t, "a=1\nb=2", content)
+
+	// the file without header is read as plain content
+	assert.Nil(t, ioutil.WriteFile(GetFileName("plain@@group@@", dir), []byte("plain"), 0644))
+	content, err = ReadConfigFromFile("plain@@group@@", dir)
+	assert.Nil(t, err)
+	assert.Equal(t, "plain", content)
+
+	files, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(files))
+}
+
+func TestReadFailoverConfig(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, ioutil.WriteFile(GetFileName("plain@@group@@", dir), []byte("plain"), 0644))
+	content, err := ReadFailoverConfig("plain@@group@@", dir)
+	assert.Nil(t, err)
+	assert.Equal(t, "plain", content)
+
+	// the snapshot copied and edited by the operator is not quarantined
+	WriteConfigToFile("edited@@group@@", dir, "a=1")
+	fileName := GetFileName("edited@@group@@", dir)
+	b, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(fileName, []byte(strings.Replace(string(b), "a=1", "a=2", 1)), 0644))
+	content, err = ReadFailoverConfig("edited@@group@@", dir)
+	assert.Nil(t, err)
+	assert.Equal(t, "a=2", content)
+	_, err = os.Stat(fileName)
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, quarantineDirName))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = ReadFailoverConfig("missing@@group@@", dir)
+	assert.NotNil(t, err)
+}
+
+func TestReadCorruptedCacheFile(t *testing.T) {
+	dir := t.TempDir()
+	WriteServicesToFile(model.Service{Name: "DEFAULT_GROUP@@demo", Clusters: "a"}, dir)
+	WriteServicesToFile(model.Service{Name: "DEFAULT_GROUP@@broken", Clusters: "a"}, dir)
+	fileName := filepath.Join(dir, "DEFAULT_GROUP@@broken@@a")
+	b, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+	// truncate the content as a crash would do
+	assert.Nil(t, ioutil.WriteFile(fileName, b[:len(b)-10], 0644))
+
+	services := ReadServicesFromFile(dir)
+	assert.Equal(t, 1, len(services))
+	assert.Equal(t, "DEFAULT_GROUP@@demo", services["DEFAULT_GROUP@@demo@@a"].Name)
+	_, err = os.Stat(fileName)
+	assert.True(t, os.IsNotExist(err))
+	quarantined, err := ioutil.ReadDir(filepath.Join(dir, quarantineDirName))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(quarantined))
+}
+
+func TestPruneCacheFiles(t *testing.T) {
+	dir := t.TempDir()
+	WriteConfigToFile("fresh@@group@@", dir, "fresh")
+	WriteConfigToFile("stale@@group@@", dir, "stale")
+	b, err := ioutil.ReadFile(GetFileName("stale@@group@@", dir))
+	assert.Nil(t, err)
+	// rewrite the header with an old write time
+	old := "#nacos-cache v1 md5=" + strings.SplitN(strings.SplitN(string(b), "md5=", 2)[1], " ", 2)[0] + " ts=1\nstale"
+	assert.Nil(t, ioutil.WriteFile(GetFileName("stale@@group@@", dir), []byte(old), 0644))
+	tempName := filepath.Join(dir, ".fresh@@group@@.123.tmp")
+	assert.Nil(t, ioutil.WriteFile(tempName, []byte("partial"), 0644))
+	oldTime := time.Now().Add(-2 * time.Hour)
+	assert.Nil(t, os.Chtimes(tempName, oldTime, oldTime))
+
+	assert.Equal(t, 2, PruneCacheFiles(dir, time.Hour))
+	content, err := ReadConfigFromFile("fresh@@group@@", dir)
+	assert.Nil(t, err)
+	assert.Equal(t, "fresh", content)
+	_, err = ReadConfigFromFile("stale@@group@@", dir)
+	assert.NotNil(t, err)
+}
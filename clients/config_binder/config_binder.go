@@ -0,0 +1,126 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_binder
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/clients/config_client"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
+)
+
+// ConfigBinder decodes the config content into go values according to the config type
+type ConfigBinder struct {
+	client config_client.IConfigClient
+}
+
+// Binding holds the latest decoded value of a listened config
+type Binding struct {
+	client     config_client.IConfigClient
+	handle     vo.ListenerHandle
+	configType vo.ConfigType
+	valueType  reflect.Type
+	value      atomic.Value
+	mutex      sync.Mutex
+	lastErr    error
+}
+
+func NewConfigBinder(client config_client.IConfigClient) *ConfigBinder {
+	return &ConfigBinder{client: client}
+}
+
+// GetConfigInto gets the config and decodes it into target
+// dataId  require
+// group   require
+// type    optional, the extension of dataId is used when it is empty
+func (binder *ConfigBinder) GetConfigInto(param vo.ConfigParam, target interface{}) error {
+	content, err := binder.client.GetConfig(param)
+	if err != nil {
+		return err
+	}
+	return Decode(util.GetConfigType(param.DataId, param.Type), content, target)
+}
+
+// BindConfig decodes the config into target and listens the config, a freshly decoded value
+// is swapped in when the config changes, use Binding.Load to get it.
+// The changes are delivered to the binding in order, the config which does not exist is decoded as the zero value.
+// A decode error keeps the last good value and is reported by Binding.LastError,
+// param.OnChange is called after each change is handled if it is not nil
+func (binder *ConfigBinder) BindConfig(param vo.ConfigParam, target interface{}) (*Binding, error) {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return nil, errors.New("[config_binder.BindConfig] target must be a non-nil pointer")
+	}
+	if err := binder.GetConfigInto(param, target); err != nil {
+		return nil, err
+	}
+	binding := &Binding{
+		client:     binder.client,
+		configType: util.GetConfigType(param.DataId, param.Type),
+		valueType:  value.Type().Elem(),
+	}
+	binding.value.Store(target)
+
+	onChange := param.OnChange
+	param.OnChange = func(namespace, group, dataId, data string) {
+		binding.update(data)
+		if onChange != nil {
+			onChange(namespace, group, dataId, data)
+		}
+	}
+	handle, err := binder.client.ListenConfig(param)
+	if err != nil {
+		return nil, err
+	}
+	binding.handle = handle
+	return binding, nil
+}
+
+func (binding *Binding) update(content string) {
+	newValue := reflect.New(binding.valueType).Interface()
+	err := Decode(binding.configType, content, newValue)
+	binding.mutex.Lock()
+	binding.lastErr = err
+	binding.mutex.Unlock()
+	if err != nil {
+		logger.Errorf("[config_binder.Binding] decode config error, keep the last good value, err:%+v", err)
+		return
+	}
+	binding.value.Store(newValue)
+}
+
+// Load returns the latest decoded value, it is a pointer of the same type as the bound target
+func (binding *Binding) Load() interface{} {
+	return binding.value.Load()
+}
+
+// LastError returns the error of the last decoding, nil if it succeeded
+func (binding *Binding) LastError() error {
+	binding.mutex.Lock()
+	defer binding.mutex.Unlock()
+	return binding.lastErr
+}
+
+// Close stops listening the config, the last value is still available by Load
+func (binding *Binding) Close() error {
+	return binding.client.CancelListener(binding.handle)
+}
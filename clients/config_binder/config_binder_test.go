@@ -0,0 +1,161 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_binder
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/clients/config_client"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
+)
+
+type fakeConfigClient struct {
+	config_client.IConfigClient
+	mutex     sync.Mutex
+	content   string
+	listener  vo.Listener
+	cancelled bool
+}
+
+func (c *fakeConfigClient) GetConfig(param vo.ConfigParam) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.content, nil
+}
+
+// change sets the content on the fake server and notifies the listener
+func (c *fakeConfigClient) change(content string) {
+	c.mutex.Lock()
+	c.content = content
+	c.mutex.Unlock()
+	c.listener("", "group", "server", content)
+}
+
+func (c *fakeConfigClient) ListenConfig(param vo.ConfigParam) (vo.ListenerHandle, error) {
+	c.listener = param.OnChange
+	return vo.ListenerHandle(1), nil
+}
+
+func (c *fakeConfigClient) CancelListener(handle vo.ListenerHandle) error {
+	c.cancelled = true
+	return nil
+}
+
+type serverConfig struct {
+	Name    string        `json:"name" yaml:"name" xml:"name"`
+	Port    int           `json:"port" yaml:"port" xml:"port"`
+	Timeout time.Duration `json:"timeout" yaml:"timeout" xml:"timeout"`
+	Hosts   []string      `json:"hosts" yaml:"hosts" xml:"hosts"`
+	Db      struct {
+		Url string `properties:"url"`
+	} `json:"db" yaml:"db" xml:"db"`
+}
+
+func TestDecode(t *testing.T) {
+	var properties serverConfig
+	err := Decode(vo.PROPERTIES, "name=demo\nport=8080\ntimeout=3s\nhosts=a, b\ndb.url=mysql://", &properties)
+	assert.Nil(t, err)
+	assert.Equal(t, "demo", properties.Name)
+	assert.Equal(t, 8080, properties.Port)
+	assert.Equal(t, 3*time.Second, properties.Timeout)
+	assert.Equal(t, []string{"a", "b"}, properties.Hosts)
+	assert.Equal(t, "mysql://", properties.Db.Url)
+
+	var yamlConfig serverConfig
+	err = Decode(vo.YAML, "name: demo\nport: 8080\nhosts:\n  - a\n  - b\n", &yamlConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, "demo", yamlConfig.Name)
+	assert.Equal(t, []string{"a", "b"}, yamlConfig.Hosts)
+
+	var xmlConfig serverConfig
+	err = Decode(vo.XML, "<config><name>demo</name><port>8080</port></config>", &xmlConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, 8080, xmlConfig.Port)
+
+	var text string
+	err = Decode(vo.TEXT, "hello", &text)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", text)
+
+	err = Decode(vo.PROPERTIES, "port=abc", &properties)
+	assert.NotNil(t, err)
+	err = Decode(vo.JSON, "{}", properties)
+	assert.NotNil(t, err)
+}
+
+func TestGetConfigInto(t *testing.T) {
+	client := &fakeConfigClient{content: `{"name":"demo","port":8080}`}
+	var config serverConfig
+	err := NewConfigBinder(client).GetConfigInto(vo.ConfigParam{DataId: "server.json", Group: "group"}, &config)
+	assert.Nil(t, err)
+	assert.Equal(t, "demo", config.Name)
+	assert.Equal(t, 8080, config.Port)
+}
+
+func TestBindConfig(t *testing.T) {
+	client := &fakeConfigClient{content: `{"name":"demo","port":8080}`}
+	var config serverConfig
+	changed := 0
+	binding, err := NewConfigBinder(client).BindConfig(vo.ConfigParam{
+		DataId: "server",
+		Group:  "group",
+		Type:   vo.JSON,
+		OnChange: func(namespace, group, dataId, data string) {
+			changed++
+		},
+	}, &config)
+	assert.Nil(t, err)
+	assert.Equal(t, 8080, binding.Load().(*serverConfig).Port)
+
+	client.change(`{"name":"demo","port":9090}`)
+	assert.Nil(t, binding.LastError())
+	assert.Equal(t, 9090, binding.Load().(*serverConfig).Port)
+	assert.Equal(t, 8080, config.Port)
+
+	// the last good value is kept when decode failed
+	client.change(`{"port":`)
+	assert.NotNil(t, binding.LastError())
+	assert.Equal(t, 9090, binding.Load().(*serverConfig).Port)
+	assert.Equal(t, 2, changed)
+
+	assert.Nil(t, binding.Close())
+	assert.True(t, client.cancelled)
+}
+
+func TestBindConfigBackToBack(t *testing.T) {
+	// the config does not exist yet
+	client := &fakeConfigClient{}
+	var config serverConfig
+	binding, err := NewConfigBinder(client).BindConfig(vo.ConfigParam{DataId: "server.json", Group: "group"}, &config)
+	assert.Nil(t, err)
+	assert.Equal(t, serverConfig{}, *binding.Load().(*serverConfig))
+
+	// the changes are delivered in order by the client, the pushed content is decoded
+	client.listener("", "group", "server", `{"port":8081}`)
+	client.listener("", "group", "server", `{"port":8082}`)
+	assert.Nil(t, binding.LastError())
+	assert.Equal(t, 8082, binding.Load().(*serverConfig).Port)
+
+	// the deleted config is the zero value
+	client.listener("", "group", "server", "")
+	assert.Nil(t, binding.LastError())
+	assert.Equal(t, 0, binding.Load().(*serverConfig).Port)
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_binder
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
+)
+
+// Decode decodes the content of the config type into target, target must be a non-nil pointer.
+// The empty content, which is got for the config not existing, sets target to the zero value
+func Decode(configType vo.ConfigType, content string, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return errors.New("[config_binder.Decode] target must be a non-nil pointer")
+	}
+	if len(content) == 0 {
+		value.Elem().Set(reflect.Zero(value.Elem().Type()))
+		return nil
+	}
+	switch configType {
+	case vo.JSON:
+		return json.Unmarshal([]byte(content), target)
+	case vo.YAML:
+		return yaml.Unmarshal([]byte(content), target)
+	case vo.XML:
+		return xml.Unmarshal([]byte(content), target)
+	case vo.PROPERTIES:
+		properties, err := util.ParseProperties(content)
+		if err != nil {
+			return err
+		}
+		return decodeProperties(properties, value.Elem())
+	case vo.TEXT, vo.HTML:
+		if value.Elem().Kind() != reflect.String {
+			return fmt.Errorf("[config_binder.Decode] the config of type %s can only be decoded into *string", configType)
+		}
+		value.Elem().SetString(content)
+		return nil
+	default:
+		return fmt.Errorf("[config_binder.Decode] unsupported config type:%s", configType)
+	}
+}
+
+// Decode the properties into map[string]string or struct, the struct field is matched by
+// the properties tag or the case-insensitive field name, nested struct uses the dotted prefix
+func decodeProperties(properties map[string]string, value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String || value.Type().Elem().Kind() != reflect.String {
+			return errors.New("[config_binder.Decode] properties can only be decoded into map[string]string")
+		}
+		if value.IsNil() {
+			value.Set(reflect.MakeMap(value.Type()))
+		}
+		for k, v := range properties {
+			value.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(v))
+		}
+		return nil
+	case reflect.Struct:
+		lowerProperties := make(map[string]string, len(properties))
+		for k, v := range properties {
+			lowerProperties[strings.ToLower(k)] = v
+		}
+		return decodePropertiesStruct(lowerProperties, "", value)
+	default:
+		return fmt.Errorf("[config_binder.Decode] properties can not be decoded into %s", value.Type())
+	}
+}
+
+func decodePropertiesStruct(properties map[string]string, prefix string, value reflect.Value) error {
+	valueType := value.Type()
+	for i := 0; i < value.NumField(); i++ {
+		field := valueType.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		name := field.Tag.Get("properties")
+		if name == "-" {
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+		key := prefix + strings.ToLower(name)
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.Struct && fieldValue.Type() != reflect.TypeOf(time.Time{}) {
+			if err := decodePropertiesStruct(properties, key+".", fieldValue); err != nil {
+				return err
+			}
+			continue
+		}
+		raw, ok := properties[key]
+		if !ok {
+			continue
+		}
+		if err := setPropertyValue(fieldValue, raw); err != nil {
+			return fmt.Errorf("[config_binder.Decode] key:%s err:%v", key, err)
+		}
+	}
+	return nil
+}
+
+func setPropertyValue(value reflect.Value, raw string) error {
+	if value.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(d))
+		return nil
+	}
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(f)
+	case reflect.Slice:
+		items := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(value.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setPropertyValue(slice.Index(i), strings.TrimSpace(item)); err != nil {
+				return err
+			}
+		}
+		value.Set(slice)
+	case reflect.Ptr:
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return setPropertyValue(value.Elem(), raw)
+	default:
+		return fmt.Errorf("unsupported field type %s", value.Type())
+	}
+	return nil
+}
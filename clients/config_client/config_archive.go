@@ -0,0 +1,323 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/nacos_error"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
+)
+
+// The archive has the same layout as the one exported by nacos console, each config is stored
+// as the entry group/dataId, and the app names are stored in the properties entry .meta.yml.
+// The type, desc and tags are stored in .meta.yml too, with the suffixes .type, .desc and .tags
+// instead of .app, nacos console ignores them when importing
+const configArchiveMetaName = ".meta.yml"
+
+const (
+	configArchiveMetaApp  = "app"
+	configArchiveMetaType = "type"
+	configArchiveMetaDesc = "desc"
+	configArchiveMetaTags = "tags"
+)
+
+type archiveConfig struct {
+	dataId     string
+	group      string
+	content    string
+	appName    string
+	configType string
+	desc       string
+	configTags string
+}
+
+func (client *ConfigClient) ExportConfigs(param vo.SearchConfigParam) (io.Reader, error) {
+	return client.ExportConfigsWithContext(context.Background(), param)
+}
+
+// The configs are searched page by page rather than exported by server, so that the archive can
+// be built whatever the server version is, the metadata of each config is queried with its detail.
+// The contents are exported as stored on server, the config filters are not applied, so the cipher-
+// configs stay encrypted in the archive.
+func (client *ConfigClient) ExportConfigsWithContext(ctx context.Context, param vo.SearchConfigParam) (io.Reader, error) {
+	if len(param.Search) == 0 {
+		param.Search = "blur"
+	}
+	clientConfig, _ := client.GetClientConfig()
+	it := client.SearchConfigAllWithContext(ctx, vo.SearchConfigAllParam{SearchConfigParam: param})
+	defer it.Close()
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+	var meta strings.Builder
+	count := 0
+	for it.Next() {
+		item := it.Item()
+		count++
+		writer, err := zipWriter.Create(item.Group + "/" + item.DataId)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = writer.Write([]byte(item.Content)); err != nil {
+			return nil, err
+		}
+		detail, err := client.configProxy.GetConfigDetailProxy(ctx, vo.ConfigParam{DataId: item.DataId, Group: item.Group},
+			clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+		if err != nil {
+			// the config deleted after it's searched is exported without the metadata
+			if nacosErr, ok := err.(*nacos_error.NacosError); !ok || nacosErr.ErrorCode() != "404" {
+				return nil, err
+			}
+			detail = &model.ConfigDetail{AppName: item.Appname}
+		}
+		writeConfigArchiveMeta(&meta, item.Group, item.DataId, configArchiveMetaApp, detail.AppName)
+		writeConfigArchiveMeta(&meta, item.Group, item.DataId, configArchiveMetaType, detail.Type)
+		writeConfigArchiveMeta(&meta, item.Group, item.DataId, configArchiveMetaDesc, detail.Desc)
+		writeConfigArchiveMeta(&meta, item.Group, item.DataId, configArchiveMetaTags, detail.ConfigTags)
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	writer, err := zipWriter.Create(configArchiveMetaName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = writer.Write([]byte(meta.String())); err != nil {
+		return nil, err
+	}
+	if err = zipWriter.Close(); err != nil {
+		return nil, err
+	}
+	logger.Infof("[client.ExportConfigs] export %d configs", count)
+	return buf, nil
+}
+
+func (client *ConfigClient) ImportConfigs(archive io.Reader, policy vo.ImportPolicy) (*model.ConfigImportResult, error) {
+	return client.ImportConfigsWithContext(context.Background(), archive, policy)
+}
+
+// The configs are published one by one, the failure of one config does not stop the others,
+// it is reported in the result instead.
+func (client *ConfigClient) ImportConfigsWithContext(ctx context.Context, archive io.Reader,
+	policy vo.ImportPolicy) (*model.ConfigImportResult, error) {
+	if policy != vo.ABORT && policy != vo.SKIP && policy != vo.OVERWRITE {
+		return nil, errors.New("[client.ImportConfigs] policy must be ABORT, SKIP or OVERWRITE")
+	}
+	configs, result, err := readConfigArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+	if policy == vo.ABORT {
+		var conflicts []bool
+		conflictCount := 0
+		for _, config := range configs {
+			exist, err := client.configExists(ctx, config.dataId, config.group)
+			if err != nil {
+				return nil, err
+			}
+			if exist {
+				conflictCount++
+			}
+			conflicts = append(conflicts, exist)
+		}
+		if conflictCount > 0 {
+			for i, config := range configs {
+				if conflicts[i] {
+					addImportItem(result, config, model.CONFIG_IMPORT_FAILED, "config already exists")
+				} else {
+					addImportItem(result, config, model.CONFIG_IMPORT_ABORTED, "import aborted")
+				}
+			}
+			return result, errors.New("[client.ImportConfigs] import aborted, " + strconv.Itoa(conflictCount) +
+				" configs already exist")
+		}
+	}
+	clientConfig, _ := client.GetClientConfig()
+	for _, config := range configs {
+		if policy == vo.SKIP {
+			exist, err := client.configExists(ctx, config.dataId, config.group)
+			if err != nil {
+				addImportItem(result, config, model.CONFIG_IMPORT_FAILED, err.Error())
+				continue
+			}
+			if exist {
+				addImportItem(result, config, model.CONFIG_IMPORT_SKIPPED, "config already exists")
+				continue
+			}
+		}
+		param := vo.ConfigParam{
+			DataId:     config.dataId,
+			Group:      config.group,
+			Content:    config.content,
+			Type:       vo.ConfigType(config.configType),
+			Desc:       config.desc,
+			ConfigTags: config.configTags,
+			AppName:    config.appName,
+		}
+		_, err = client.configProxy.PublishConfigProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+		if err != nil {
+			addImportItem(result, config, model.CONFIG_IMPORT_FAILED, err.Error())
+			continue
+		}
+		addImportItem(result, config, model.CONFIG_IMPORT_SUCCESS, "")
+	}
+	logger.Infof("[client.ImportConfigs] import configs, success:%d skip:%d fail:%d",
+		result.SuccessCount, result.SkipCount, result.FailCount)
+	return result, nil
+}
+
+func (client *ConfigClient) configExists(ctx context.Context, dataId, group string) (bool, error) {
+	page, err := client.searchConfigInner(ctx, vo.SearchConfigParam{
+		Search:   "accurate",
+		DataId:   dataId,
+		Group:    group,
+		PageSize: 1,
+	})
+	if err != nil {
+		return false, err
+	}
+	return page != nil && page.TotalCount > 0, nil
+}
+
+// Read the configs of the archive, the invalid entries are reported as FAILED in the result
+func readConfigArchive(archive io.Reader) ([]archiveConfig, *model.ConfigImportResult, error) {
+	data, err := ioutil.ReadAll(archive)
+	if err != nil {
+		return nil, nil, err
+	}
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, errors.New("[client.ImportConfigs] invalid zip archive:" + err.Error())
+	}
+	result := &model.ConfigImportResult{}
+	meta := map[string]string{}
+	var configs []archiveConfig
+	for _, file := range zipReader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		content, err := readArchiveFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		if file.Name == configArchiveMetaName {
+			if meta, err = util.ParseProperties(content); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		names := strings.Split(file.Name, "/")
+		if len(names) != 2 || len(names[0]) == 0 || len(names[1]) == 0 {
+			addImportItem(result, archiveConfig{dataId: file.Name}, model.CONFIG_IMPORT_FAILED, "entry name is not group/dataId")
+			continue
+		}
+		configs = append(configs, archiveConfig{
+			dataId:  names[1],
+			group:   names[0],
+			content: content,
+		})
+	}
+	for i := range configs {
+		configs[i].appName = meta[configArchiveMetaKey(configs[i].group, configs[i].dataId, configArchiveMetaApp)]
+		configs[i].configType = meta[configArchiveMetaKey(configs[i].group, configs[i].dataId, configArchiveMetaType)]
+		configs[i].desc = meta[configArchiveMetaKey(configs[i].group, configs[i].dataId, configArchiveMetaDesc)]
+		configs[i].configTags = meta[configArchiveMetaKey(configs[i].group, configs[i].dataId, configArchiveMetaTags)]
+	}
+	return configs, result, nil
+}
+
+func readArchiveFile(file *zip.File) (string, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	content, err := ioutil.ReadAll(reader)
+	return string(content), err
+}
+
+// The key of the metadata in .meta.yml, the last '.' of dataId is replaced by '~' as nacos console does
+func configArchiveMetaKey(group, dataId, name string) string {
+	if index := strings.LastIndex(dataId, "."); index >= 0 {
+		dataId = dataId[:index] + "~" + dataId[index+1:]
+	}
+	return group + "." + dataId + "." + name
+}
+
+// Write the metadata as a properties line, the empty value is omitted
+func writeConfigArchiveMeta(meta *strings.Builder, group, dataId, name, value string) {
+	if len(value) == 0 {
+		return
+	}
+	meta.WriteString(configArchiveMetaKey(group, dataId, name) + "=" + escapePropertyValue(value) + "\n")
+}
+
+// Escape the value so that ParseProperties reads it back, the line breaks, the backslashes and
+// the leading and trailing whitespace are escaped
+func escapePropertyValue(value string) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\':
+			builder.WriteString("\\\\")
+		case '\n':
+			builder.WriteString("\\n")
+		case '\r':
+			builder.WriteString("\\r")
+		case '\t':
+			builder.WriteString("\\t")
+		case '\f':
+			builder.WriteString("\\f")
+		case ' ':
+			if i == 0 || i == len(value)-1 {
+				builder.WriteString("\\ ")
+			} else {
+				builder.WriteByte(c)
+			}
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	return builder.String()
+}
+
+func addImportItem(result *model.ConfigImportResult, config archiveConfig, status model.ConfigImportStatus, reason string) {
+	switch status {
+	case model.CONFIG_IMPORT_SUCCESS:
+		result.SuccessCount++
+	case model.CONFIG_IMPORT_SKIPPED:
+		result.SkipCount++
+	default:
+		result.FailCount++
+	}
+	result.Items = append(result.Items, model.ConfigImportItem{
+		DataId:  config.dataId,
+		Group:   config.group,
+		Status:  status,
+		Message: reason,
+	})
+}
@@ -17,21 +17,21 @@
 package config_client
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"math"
 	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/aliyun/alibaba-cloud-sdk-go/services/kms"
-
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/clients/cache"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/clients/nacos_client"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/config_filter"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/constant"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/http_agent"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
@@ -42,52 +42,127 @@ import (
 )
 
 type ConfigClient struct {
+	listenerSeq uint64
+	ctx         context.Context
+	cancel      context.CancelFunc
 	nacos_client.INacosClient
-	kmsClient        *kms.Client
-	localConfigs     []vo.ConfigParam
-	mutex            sync.Mutex
-	configProxy      ConfigProxy
-	configCacheDir   string
-	currentTaskCount int32
-	cacheMap         cache.ConcurrentMap
-	schedulerMap     cache.ConcurrentMap
+	configFilters     []config_filter.ConfigFilter
+	localConfigs      []vo.ConfigParam
+	mutex             sync.Mutex
+	wg                sync.WaitGroup
+	configProxy       ConfigProxy
+	configCacheStore  cache.CacheStore // the snapshot store
+	configFailoverDir string
+	currentTaskCount  int32
+	cacheMap          cache.ConcurrentMap
+	schedulerMap      cache.ConcurrentMap
+	listenerKeyMap    cache.ConcurrentMap
+	listenerDoneMap   cache.ConcurrentMap // the listener id -> the channel closed when the listener is removed
+	fuzzyListenerMap  cache.ConcurrentMap
+	warmUp            *util.WarmUp
+	warmUpKeys        map[string]bool     // the cache keys of the warm-up configs, it's not changed after created
+	warmUpContents    cache.ConcurrentMap // the cache key -> the content of the warm-up config on server
+	listenBatchSize   int
+	listenMaxFailures int
 }
 
 const (
-	perTaskConfigSize = 3000
-	executorErrDelay  = 5 * time.Second
+	perTaskConfigSize  = 3000
+	listenMaxFailures  = 3
+	executorErrDelay   = 5 * time.Second
+	failoverCheckDelay = 5 * time.Second
+	warmUpRetryDelay   = 1 * time.Second
 )
 
 type cacheData struct {
-	isInitializing    bool
-	dataId            string
-	group             string
-	content           string
-	tenant            string
-	cacheDataListener *cacheDataListener
-	md5               string
-	appName           string
-	taskId            int
+	isInitializing bool
+	dataId         string
+	group          string
+	content        string
+	tenant         string
+	listeners      cache.ConcurrentMap
+	md5            string
+	appName        string
+	taskId         int
+	isBeta         bool
+	useFailover    bool
+	// the health of the long polling
+	lastCheckTime  time.Time
+	listenFailures int
+	listenError    error
 }
 
 type cacheDataListener struct {
-	listener vo.Listener
-	lastMd5  string
+	listener       vo.Listener
+	eventListener  vo.ChangeEventListener
+	healthListener vo.ListenHealthListener
+	configType     vo.ConfigType
+	lastMd5        string
+	lastContent    string
+	// the notifications of the listener are delivered in order
+	queue listenerQueue
+}
+
+// listenerQueue runs the tasks one by one in a goroutine, which exits when there is no task left
+type listenerQueue struct {
+	mutex   sync.Mutex
+	tasks   []func()
+	running bool
+}
+
+func (queue *listenerQueue) push(wg *sync.WaitGroup, task func()) {
+	queue.mutex.Lock()
+	queue.tasks = append(queue.tasks, task)
+	if queue.running {
+		queue.mutex.Unlock()
+		return
+	}
+	queue.running = true
+	queue.mutex.Unlock()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			queue.mutex.Lock()
+			if len(queue.tasks) == 0 {
+				queue.running = false
+				queue.mutex.Unlock()
+				return
+			}
+			task := queue.tasks[0]
+			queue.tasks = queue.tasks[1:]
+			queue.mutex.Unlock()
+			task()
+		}
+	}()
 }
 
 func NewConfigClient(nc nacos_client.INacosClient) (*ConfigClient, error) {
 	config := &ConfigClient{
-		cacheMap:     cache.NewConcurrentMap(),
-		schedulerMap: cache.NewConcurrentMap(),
+		cacheMap:         cache.NewConcurrentMap(),
+		schedulerMap:     cache.NewConcurrentMap(),
+		listenerKeyMap:   cache.NewConcurrentMap(),
+		listenerDoneMap:  cache.NewConcurrentMap(),
+		fuzzyListenerMap: cache.NewConcurrentMap(),
+		warmUpContents:   cache.NewConcurrentMap(),
 	}
-	config.schedulerMap.Set("root", true)
-	go config.delayScheduler(time.NewTimer(1*time.Millisecond), 500*time.Millisecond, "root", config.listenConfigExecutor())
-
+	config.ctx, config.cancel = context.WithCancel(context.Background())
 	config.INacosClient = nc
 	clientConfig, err := nc.GetClientConfig()
 	if err != nil {
 		return config, err
 	}
+	if err = checkClientConfig(clientConfig); err != nil {
+		return config, err
+	}
+	config.listenBatchSize = clientConfig.ListenBatchSize
+	if config.listenBatchSize <= 0 {
+		config.listenBatchSize = perTaskConfigSize
+	}
+	config.listenMaxFailures = clientConfig.ListenMaxFailures
+	if config.listenMaxFailures <= 0 {
+		config.listenMaxFailures = listenMaxFailures
+	}
 	serverConfig, err := nc.GetServerConfig()
 	if err != nil {
 		return config, err
@@ -110,16 +185,123 @@ func NewConfigClient(nc nacos_client.INacosClient) (*ConfigClient, error) {
 		return config, err
 	}
 	logger.GetLogger().Infof("logDir:<%s>   cacheDir:<%s>", clientConfig.LogDir, clientConfig.CacheDir)
-	config.configCacheDir = clientConfig.CacheDir + string(os.PathSeparator) + "config"
-	config.configProxy, err = NewConfigProxy(serverConfig, clientConfig, httpAgent)
+	snapshotDir := clientConfig.ConfigSnapshotDir
+	if len(snapshotDir) == 0 {
+		snapshotDir = clientConfig.CacheDir + string(os.PathSeparator) + "config"
+	}
+	config.configCacheStore, err = cache.NewCacheStore(clientConfig.CacheStoreType, snapshotDir)
+	if err != nil {
+		return config, err
+	}
+	config.configFailoverDir = clientConfig.ConfigFailoverDir
+	if len(config.configFailoverDir) == 0 {
+		config.configFailoverDir = clientConfig.CacheDir + string(os.PathSeparator) + "failover"
+	}
+	// the failover files are managed by hand, so only the snapshot is pruned
+	if clientConfig.CacheMaxAgeMs > 0 {
+		config.configCacheStore.Prune(time.Duration(clientConfig.CacheMaxAgeMs) * time.Millisecond)
+	}
+	config.configProxy, err = NewConfigProxy(config.ctx, serverConfig, clientConfig, httpAgent)
+	if err != nil {
+		config.CloseClient()
+		return config, err
+	}
 	if clientConfig.OpenKMS {
-		kmsClient, err := kms.NewClientWithAccessKey(clientConfig.RegionId, clientConfig.AccessKey, clientConfig.SecretKey)
+		kmsFilter, err := config_filter.NewKMSFilter(clientConfig.RegionId, clientConfig.AccessKey, clientConfig.SecretKey)
 		if err != nil {
+			config.CloseClient()
 			return config, err
 		}
-		config.kmsClient = kmsClient
+		config.configFilters = append(config.configFilters, kmsFilter)
+	}
+	if len(clientConfig.KeyRingFile) > 0 || len(clientConfig.KeyRingEnv) > 0 {
+		var (
+			keyRing *config_filter.KeyRing
+			keyErr  error
+		)
+		if len(clientConfig.KeyRingFile) > 0 {
+			keyRing, keyErr = config_filter.LoadKeyRingFromFile(clientConfig.KeyRingFile)
+		} else {
+			keyRing, keyErr = config_filter.LoadKeyRingFromEnv(clientConfig.KeyRingEnv)
+		}
+		if keyErr != nil {
+			config.CloseClient()
+			return config, keyErr
+		}
+		config.configFilters = append(config.configFilters, config_filter.NewAESFilter(keyRing))
+	}
+	config.configFilters = append(config.configFilters, clientConfig.ConfigFilters...)
+
+	config.warmUpKeys = make(map[string]bool, len(clientConfig.WarmUpConfigs))
+	for _, warmUpConfig := range clientConfig.WarmUpConfigs {
+		config.warmUpKeys[util.GetConfigCacheKey(warmUpConfig.DataId, warmUpConfig.Group, clientConfig.NamespaceId)] = true
+	}
+
+	// the background work starts only after the client is created successfully
+	config.schedulerMap.Set("root", true)
+	config.wg.Add(1)
+	go config.delayScheduler(time.NewTimer(1*time.Millisecond), 500*time.Millisecond, "root", config.listenConfigExecutor())
+	config.warmUpConfigs(clientConfig)
+	return config, nil
+}
+
+// checkClientConfig validates the client config before any background work is started
+func checkClientConfig(clientConfig constant.ClientConfig) error {
+	if clientConfig.OpenKMS && (len(clientConfig.KeyRingFile) > 0 || len(clientConfig.KeyRingEnv) > 0) {
+		return errors.New("[client.NewConfigClient] OpenKMS and the local key ring can not be both enabled")
+	}
+	for _, config := range clientConfig.WarmUpConfigs {
+		if len(config.DataId) <= 0 || len(config.Group) <= 0 {
+			return errors.New("[client.NewConfigClient] dataId and group of the warm-up config can not be empty")
+		}
+	}
+	return nil
+}
+
+// Load the warm-up configs in parallel, and wait for them at most WarmUpTimeoutMs,
+// the configs not loaded in time keep loading in background. The loaded contents are kept in memory
+// and updated by the long polling, so GetConfig of the warm-up configs does not request server
+func (client *ConfigClient) warmUpConfigs(clientConfig constant.ClientConfig) {
+	configs := clientConfig.WarmUpConfigs
+	for _, config := range configs {
+		client.addListener(vo.ConfigParam{DataId: config.DataId, Group: config.Group}, clientConfig.NamespaceId, false)
+	}
+	client.warmUp = util.NewWarmUp(client.ctx, &client.wg, len(configs), warmUpRetryDelay, func(ctx context.Context, index int) error {
+		param := vo.ConfigParam{DataId: configs[index].DataId, Group: configs[index].Group}
+		content, source, _, err := client.getConfigInner(ctx, param)
+		if err != nil {
+			logger.Warnf("[client.warmUp] load config failed, dataId: %s, group: %s, err:%+v", param.DataId, param.Group, err)
+			return err
+		}
+		// the content got by the long polling is newer
+		if source != vo.SOURCE_FAILOVER {
+			client.warmUpContents.SetIfAbsent(util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId), content)
+		}
+		return nil
+	})
+	if len(configs) == 0 {
+		return
+	}
+	timeoutMs := clientConfig.WarmUpTimeoutMs
+	if timeoutMs == 0 {
+		timeoutMs = clientConfig.TimeoutMs
+	}
+	ctx, cancel := context.WithTimeout(client.ctx, time.Duration(timeoutMs)*time.Millisecond)
+	defer cancel()
+	if err := client.warmUp.WaitReady(ctx); err != nil {
+		logger.Warnf("[client.NewConfigClient] the warm-up configs are not loaded in %d ms, keep loading them in background", timeoutMs)
+	}
+}
+
+func (client *ConfigClient) Ready() bool {
+	return client.warmUp != nil && client.warmUp.Ready()
+}
+
+func (client *ConfigClient) WaitReady(ctx context.Context) error {
+	if client.warmUp == nil {
+		return errors.New("[client.WaitReady] the client is not created successfully")
 	}
-	return config, err
+	return client.warmUp.WaitReady(ctx)
 }
 
 func (client *ConfigClient) sync() (clientConfig constant.ClientConfig,
@@ -143,133 +325,489 @@ func (client *ConfigClient) sync() (clientConfig constant.ClientConfig,
 }
 
 func (client *ConfigClient) GetConfig(param vo.ConfigParam) (content string, err error) {
-	content, err = client.getConfigInner(param)
+	return client.GetConfigWithContext(context.Background(), param)
+}
 
+func (client *ConfigClient) GetConfigWithContext(ctx context.Context, param vo.ConfigParam) (content string, err error) {
+	content, _, err = client.GetConfigWithSourceWithContext(ctx, param)
+	return content, err
+}
+
+func (client *ConfigClient) GetConfigWithSource(param vo.ConfigParam) (content string, source vo.ConfigSource, err error) {
+	return client.GetConfigWithSourceWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) GetConfigWithSourceWithContext(ctx context.Context, param vo.ConfigParam) (content string,
+	source vo.ConfigSource, err error) {
+	clientConfig, _ := client.GetClientConfig()
+	var ok bool
+	if content, ok = client.getWarmUpContent(util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId)); ok {
+		source = vo.SOURCE_MEMORY
+	} else if content, source, _, err = client.getConfigInner(ctx, param); err != nil {
+		return "", "", err
+	}
+
+	content, err = client.doFilter(config_filter.USAGE_GET, clientConfig.NamespaceId, param.Group, param.DataId, content)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
+	return content, source, nil
+}
 
-	return client.decrypt(param.DataId, content)
+// getWarmUpContent returns the content of the warm-up config kept in memory, the failover config takes priority over it
+func (client *ConfigClient) getWarmUpContent(key string) (string, bool) {
+	content, ok := client.warmUpContents.Get(key)
+	if !ok {
+		return "", false
+	}
+	if _, useFailover := client.readFailoverConfig(key); useFailover {
+		return "", false
+	}
+	return content.(string), true
 }
 
-func (client *ConfigClient) decrypt(dataId, content string) (string, error) {
-	if client.kmsClient != nil && strings.HasPrefix(dataId, "cipher-") {
-		request := kms.CreateDecryptRequest()
-		request.Method = "POST"
-		request.Scheme = "https"
-		request.AcceptFormat = "json"
-		request.CiphertextBlob = content
-		response, err := client.kmsClient.Decrypt(request)
-		if err != nil {
-			return "", fmt.Errorf("kms decrypt failed: %v", err)
-		}
-		content = response.Plaintext
+// Run the config filters on the content
+func (client *ConfigClient) doFilter(usage config_filter.Usage, tenant, group, dataId, content string) (string, error) {
+	if len(client.configFilters) == 0 {
+		return content, nil
+	}
+	config := &config_filter.ConfigContext{
+		Usage:     usage,
+		Namespace: tenant,
+		Group:     group,
+		DataId:    dataId,
+		Content:   content,
 	}
-	return content, nil
+	if err := config_filter.DoFilters(client.configFilters, config); err != nil {
+		return "", err
+	}
+	return config.Content, nil
 }
 
-func (client *ConfigClient) encrypt(dataId, content string) (string, error) {
-	if client.kmsClient != nil && strings.HasPrefix(dataId, "cipher-") {
-		request := kms.CreateEncryptRequest()
-		request.Method = "POST"
-		request.Scheme = "https"
-		request.AcceptFormat = "json"
-		request.KeyId = "alias/acs/acm" // use default key
-		request.Plaintext = content
-		response, err := client.kmsClient.Encrypt(request)
-		if err != nil {
-			return "", fmt.Errorf("kms encrypt failed: %v", err)
+func (client *ConfigClient) GetConfigDetail(param vo.ConfigParam) (*model.ConfigDetail, error) {
+	return client.GetConfigDetailWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) GetConfigDetailWithContext(ctx context.Context, param vo.ConfigParam) (*model.ConfigDetail, error) {
+	if len(param.DataId) <= 0 {
+		return nil, errors.New("[client.GetConfigDetail] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return nil, errors.New("[client.GetConfigDetail] param.group can not be empty")
+	}
+	clientConfig, _ := client.GetClientConfig()
+	configDetail, err := client.configProxy.GetConfigDetailProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	if err != nil {
+		logger.Errorf("get config detail from server error:%+v ", err)
+		if nacosErr, ok := err.(*nacos_error.NacosError); ok {
+			if nacosErr.ErrorCode() == "404" {
+				return nil, nil
+			}
+			if nacosErr.ErrorCode() == "403" {
+				return nil, errors.New("get config forbidden")
+			}
 		}
-		content = response.CiphertextBlob
+		return nil, err
+	}
+	configDetail.Content, err = client.doFilter(config_filter.USAGE_GET, clientConfig.NamespaceId, param.Group, param.DataId, configDetail.Content)
+	if err != nil {
+		return nil, err
 	}
-	return content, nil
+	return configDetail, nil
 }
 
-func (client *ConfigClient) getConfigInner(param vo.ConfigParam) (content string, err error) {
+// Get the config from the failover file if it exists, otherwise from server, or from the snapshot if the server
+// is unavailable, isBeta is true if the server returns the beta content to this client
+func (client *ConfigClient) getConfigInner(ctx context.Context, param vo.ConfigParam) (content string, source vo.ConfigSource,
+	isBeta bool, err error) {
 	if len(param.DataId) <= 0 {
 		err = errors.New("[client.GetConfig] param.dataId can not be empty")
-		return "", err
+		return "", "", false, err
 	}
 	if len(param.Group) <= 0 {
 		err = errors.New("[client.GetConfig] param.group can not be empty")
-		return "", err
+		return "", "", false, err
 	}
 	clientConfig, _ := client.GetClientConfig()
 	cacheKey := util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId)
-	content, err = client.configProxy.GetConfigProxy(param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	if content, ok := client.readFailoverConfig(cacheKey); ok {
+		logger.Warnf("[client.GetConfig] use failover config, dataId: %s, group: %s, namespaceId: %s.", param.DataId, param.Group, clientConfig.NamespaceId)
+		return content, vo.SOURCE_FAILOVER, false, nil
+	}
+	content, isBeta, err = client.configProxy.GetConfigWithBetaFlagProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
 
 	if err != nil {
 		logger.Errorf("get config from server error:%+v ", err)
 		if _, ok := err.(*nacos_error.NacosError); ok {
 			nacosErr := err.(*nacos_error.NacosError)
 			if nacosErr.ErrorCode() == "404" {
-				cache.WriteConfigToFile(cacheKey, client.configCacheDir, "")
+				client.writeSnapshot(cacheKey, "")
 				logger.Warnf("[client.GetConfig] config not found, dataId: %s, group: %s, namespaceId: %s.", param.DataId, param.Group, clientConfig.NamespaceId)
-				return "", nil
+				return "", vo.SOURCE_SERVER, false, nil
 			}
 			if nacosErr.ErrorCode() == "403" {
-				return "", errors.New("get config forbidden")
+				return "", "", false, errors.New("get config forbidden")
 			}
 		}
-		content, err = cache.ReadConfigFromFile(cacheKey, client.configCacheDir)
+		if ctx.Err() != nil {
+			return "", "", false, ctx.Err()
+		}
+		content, err = client.configCacheStore.Get(cacheKey)
 		if err != nil {
 			logger.Errorf("get config from cache  error:%+v ", err)
-			return "", errors.New("read config from both server and cache fail")
+			return "", "", false, errors.New("read config from both server and cache fail")
 		}
+		return content, vo.SOURCE_SNAPSHOT, false, nil
+	}
+	client.writeSnapshot(cacheKey, content)
+	return content, vo.SOURCE_SERVER, isBeta, nil
+}
 
-	} else {
-		cache.WriteConfigToFile(cacheKey, client.configCacheDir, content)
+func (client *ConfigClient) writeSnapshot(cacheKey string, content string) {
+	if err := client.configCacheStore.Put(cacheKey, content); err != nil {
+		logger.Errorf("failed to write config snapshot:%s ,value:%s ,err:%+v", cacheKey, content, err)
+	}
+}
+
+// Read the config from the failover directory, ok is false if there is no failover file of the config
+func (client *ConfigClient) readFailoverConfig(cacheKey string) (content string, ok bool) {
+	if _, err := os.Stat(cache.GetFileName(cacheKey, client.configFailoverDir)); err != nil {
+		return "", false
+	}
+	content, err := cache.ReadFailoverConfig(cacheKey, client.configFailoverDir)
+	if err != nil {
+		logger.Errorf("[client.readFailoverConfig] read failover config error:%+v", err)
+		return "", false
 	}
-	return content, nil
+	return content, true
 }
 
 func (client *ConfigClient) PublishConfig(param vo.ConfigParam) (published bool,
+	err error) {
+	return client.PublishConfigWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) PublishConfigWithContext(ctx context.Context, param vo.ConfigParam) (published bool,
 	err error) {
 	if len(param.DataId) <= 0 {
-		err = errors.New("[client.PublishConfig] param.dataId can not be empty")
+		return false, errors.New("[client.PublishConfig] param.dataId can not be empty")
 	}
 	if len(param.Group) <= 0 {
-		err = errors.New("[client.PublishConfig] param.group can not be empty")
+		return false, errors.New("[client.PublishConfig] param.group can not be empty")
 	}
 	if len(param.Content) <= 0 {
-		err = errors.New("[client.PublishConfig] param.content can not be empty")
+		return false, errors.New("[client.PublishConfig] param.content can not be empty")
 	}
 
-	param.Content, err = client.encrypt(param.DataId, param.Content)
+	clientConfig, _ := client.GetClientConfig()
+	param.Content, err = client.doFilter(config_filter.USAGE_PUBLISH, clientConfig.NamespaceId, param.Group, param.DataId, param.Content)
 	if err != nil {
 		return false, err
 	}
+	return client.configProxy.PublishConfigProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+}
+
+func (client *ConfigClient) PublishConfigCAS(param vo.ConfigParam, expectedMd5 string) (published bool,
+	err error) {
+	return client.PublishConfigCASWithContext(context.Background(), param, expectedMd5)
+}
+
+func (client *ConfigClient) PublishConfigCASWithContext(ctx context.Context, param vo.ConfigParam, expectedMd5 string) (published bool,
+	err error) {
+	if len(param.DataId) <= 0 {
+		return false, errors.New("[client.PublishConfigCAS] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return false, errors.New("[client.PublishConfigCAS] param.group can not be empty")
+	}
+	if len(param.Content) <= 0 {
+		return false, errors.New("[client.PublishConfigCAS] param.content can not be empty")
+	}
+	if len(expectedMd5) <= 0 {
+		return false, errors.New("[client.PublishConfigCAS] expectedMd5 can not be empty")
+	}
 	clientConfig, _ := client.GetClientConfig()
-	return client.configProxy.PublishConfigProxy(param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	param.Content, err = client.doFilter(config_filter.USAGE_PUBLISH, clientConfig.NamespaceId, param.Group, param.DataId, param.Content)
+	if err != nil {
+		return false, err
+	}
+	return client.configProxy.PublishConfigCASProxy(ctx, param, expectedMd5, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+}
+
+func (client *ConfigClient) UpdateConfigCAS(param vo.ConfigParam, maxRetries int, modify func(content string) (string, error)) (err error) {
+	return client.UpdateConfigCASWithContext(context.Background(), param, maxRetries, modify)
+}
+
+// UpdateConfigCASWithContext is the same as UpdateConfigCAS, the retries are stopped when ctx is done.
+// The config which does not exist is read as the empty content and created by the publish, the md5 of the
+// empty content is sent as the expected md5, the server does not compare it when creating the config
+func (client *ConfigClient) UpdateConfigCASWithContext(ctx context.Context, param vo.ConfigParam, maxRetries int,
+	modify func(content string) (string, error)) (err error) {
+	if len(param.DataId) <= 0 {
+		return errors.New("[client.UpdateConfigCAS] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return errors.New("[client.UpdateConfigCAS] param.group can not be empty")
+	}
+	if modify == nil {
+		return errors.New("[client.UpdateConfigCAS] modify can not be nil")
+	}
+	clientConfig, _ := client.GetClientConfig()
+	for i := 0; ; i++ {
+		// read from server only, the cached content would always conflict
+		var serverContent string
+		serverContent, err = client.configProxy.GetConfigProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+		if err != nil {
+			// the creation can not be compared and swapped, the md5 of "" would let a concurrent creator be overwritten
+			if nacosErr, ok := err.(*nacos_error.NacosError); ok && nacosErr.ErrorCode() == "404" {
+				return &nacos_error.ConfigNotFoundError{DataId: param.DataId, Group: param.Group, Tenant: clientConfig.NamespaceId}
+			}
+			return err
+		}
+		var content string
+		content, err = client.doFilter(config_filter.USAGE_GET, clientConfig.NamespaceId, param.Group, param.DataId, serverContent)
+		if err != nil {
+			return err
+		}
+		param.Content, err = modify(content)
+		if err != nil {
+			return err
+		}
+		_, err = client.PublishConfigCASWithContext(ctx, param, util.Md5(serverContent))
+		if _, ok := err.(*nacos_error.ConfigConflictError); !ok || i >= maxRetries {
+			return err
+		}
+		logger.Warnf("[client.UpdateConfigCAS] config changed concurrently, retry:%d DataId:%s Group:%s", i+1, param.DataId, param.Group)
+	}
+}
+
+func (client *ConfigClient) ListConfigHistory(param vo.ConfigParam, page vo.PageParam) (*model.ConfigHistoryPage, error) {
+	return client.ListConfigHistoryWithContext(context.Background(), param, page)
+}
+
+func (client *ConfigClient) ListConfigHistoryWithContext(ctx context.Context, param vo.ConfigParam,
+	page vo.PageParam) (*model.ConfigHistoryPage, error) {
+	if len(param.DataId) <= 0 {
+		return nil, errors.New("[client.ListConfigHistory] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return nil, errors.New("[client.ListConfigHistory] param.group can not be empty")
+	}
+	if page.PageNo <= 0 {
+		page.PageNo = 1
+	}
+	if page.PageSize <= 0 {
+		page.PageSize = 10
+	}
+	clientConfig, _ := client.GetClientConfig()
+	historyPage, err := client.configProxy.ListConfigHistoryProxy(ctx, param, page, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	if err != nil {
+		return nil, err
+	}
+	for i := range historyPage.PageItems {
+		item := &historyPage.PageItems[i]
+		item.Content, err = client.doFilter(config_filter.USAGE_GET, clientConfig.NamespaceId, item.Group, item.DataId, item.Content)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return historyPage, nil
+}
+
+func (client *ConfigClient) GetConfigHistory(nid int64) (*model.ConfigHistory, error) {
+	return client.GetConfigHistoryWithContext(context.Background(), nid)
+}
+
+func (client *ConfigClient) GetConfigHistoryWithContext(ctx context.Context, nid int64) (*model.ConfigHistory, error) {
+	clientConfig, _ := client.GetClientConfig()
+	history, err := client.configProxy.GetConfigHistoryProxy(ctx, nid, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	if err != nil {
+		return nil, err
+	}
+	history.Content, err = client.doFilter(config_filter.USAGE_GET, clientConfig.NamespaceId, history.Group, history.DataId, history.Content)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (client *ConfigClient) RollbackConfig(param vo.ConfigParam, nid int64) (bool, error) {
+	return client.RollbackConfigWithContext(context.Background(), param, nid)
+}
+
+func (client *ConfigClient) RollbackConfigWithContext(ctx context.Context, param vo.ConfigParam, nid int64) (bool, error) {
+	if len(param.DataId) <= 0 {
+		return false, errors.New("[client.RollbackConfig] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return false, errors.New("[client.RollbackConfig] param.group can not be empty")
+	}
+	clientConfig, _ := client.GetClientConfig()
+	history, err := client.configProxy.GetConfigHistoryProxy(ctx, nid, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	if err != nil {
+		return false, err
+	}
+	if history.DataId != param.DataId || history.Group != param.Group {
+		return false, errors.New("[client.RollbackConfig] history " + strconv.FormatInt(nid, 10) +
+			" does not belong to dataId:" + param.DataId + " group:" + param.Group)
+	}
+	// the history content is stored as published, so it is republished without the publish filters
+	param.Content = history.Content
+	if len(param.AppName) <= 0 {
+		param.AppName = history.AppName
+	}
+	return client.configProxy.PublishConfigProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+}
+
+func (client *ConfigClient) PublishConfigBeta(param vo.ConfigParam, betaIps []string) (published bool, err error) {
+	return client.PublishConfigBetaWithContext(context.Background(), param, betaIps)
+}
+
+func (client *ConfigClient) PublishConfigBetaWithContext(ctx context.Context, param vo.ConfigParam, betaIps []string) (published bool,
+	err error) {
+	if len(param.DataId) <= 0 {
+		return false, errors.New("[client.PublishConfigBeta] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return false, errors.New("[client.PublishConfigBeta] param.group can not be empty")
+	}
+	if len(param.Content) <= 0 {
+		return false, errors.New("[client.PublishConfigBeta] param.content can not be empty")
+	}
+	if len(betaIps) <= 0 {
+		return false, errors.New("[client.PublishConfigBeta] betaIps can not be empty")
+	}
+
+	clientConfig, _ := client.GetClientConfig()
+	param.Content, err = client.doFilter(config_filter.USAGE_PUBLISH, clientConfig.NamespaceId, param.Group, param.DataId, param.Content)
+	if err != nil {
+		return false, err
+	}
+	return client.configProxy.PublishConfigBetaProxy(ctx, param, betaIps, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+}
+
+func (client *ConfigClient) GetConfigBeta(param vo.ConfigParam) (*model.ConfigBeta, error) {
+	return client.GetConfigBetaWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) GetConfigBetaWithContext(ctx context.Context, param vo.ConfigParam) (*model.ConfigBeta, error) {
+	if len(param.DataId) <= 0 {
+		return nil, errors.New("[client.GetConfigBeta] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return nil, errors.New("[client.GetConfigBeta] param.group can not be empty")
+	}
+	clientConfig, _ := client.GetClientConfig()
+	configBeta, err := client.configProxy.GetConfigBetaProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	if err != nil || configBeta == nil {
+		return nil, err
+	}
+	configBeta.Content, err = client.doFilter(config_filter.USAGE_GET, clientConfig.NamespaceId, param.Group, param.DataId, configBeta.Content)
+	if err != nil {
+		return nil, err
+	}
+	return configBeta, nil
+}
+
+func (client *ConfigClient) StopBeta(param vo.ConfigParam) (stopped bool, err error) {
+	return client.StopBetaWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) StopBetaWithContext(ctx context.Context, param vo.ConfigParam) (stopped bool, err error) {
+	if len(param.DataId) <= 0 {
+		return false, errors.New("[client.StopBeta] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return false, errors.New("[client.StopBeta] param.group can not be empty")
+	}
+	clientConfig, _ := client.GetClientConfig()
+	return client.configProxy.StopBetaProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
 }
 
 func (client *ConfigClient) DeleteConfig(param vo.ConfigParam) (deleted bool, err error) {
+	return client.DeleteConfigWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) DeleteConfigWithContext(ctx context.Context, param vo.ConfigParam) (deleted bool, err error) {
 	if len(param.DataId) <= 0 {
-		err = errors.New("[client.DeleteConfig] param.dataId can not be empty")
+		return false, errors.New("[client.DeleteConfig] param.dataId can not be empty")
 	}
 	if len(param.Group) <= 0 {
-		err = errors.New("[client.DeleteConfig] param.group can not be empty")
+		return false, errors.New("[client.DeleteConfig] param.group can not be empty")
 	}
 
 	clientConfig, _ := client.GetClientConfig()
-	return client.configProxy.DeleteConfigProxy(param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	return client.configProxy.DeleteConfigProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
 }
 
-// Cancel Listen Config
+// Cancel Listen Config, all the listeners of the dataId and group are removed
 func (client *ConfigClient) CancelListenConfig(param vo.ConfigParam) (err error) {
 	clientConfig, err := client.GetClientConfig()
 	if err != nil {
 		logger.Errorf("[checkConfigInfo.GetClientConfig] failed,err:%+v", err)
 		return
 	}
-	client.cacheMap.Remove(util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId))
+	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId)
+	client.mutex.Lock()
+	if value, ok := client.cacheMap.Get(key); ok {
+		for _, id := range value.(cacheData).listeners.Keys() {
+			client.listenerKeyMap.Remove(id)
+			client.closeListenerDone(id)
+		}
+		client.removeCacheData(key)
+		// the warm-up config is not updated any more
+		client.warmUpContents.Remove(key)
+	}
+	client.mutex.Unlock()
 	logger.Infof("Cancel listen config DataId:%s Group:%s", param.DataId, param.Group)
-	remakeId := int(math.Ceil(float64(client.cacheMap.Count()) / float64(perTaskConfigSize)))
+	return err
+}
+
+// CancelListener removes the listener identified by handle, the other listeners of the same dataId and group keep running
+func (client *ConfigClient) CancelListener(handle vo.ListenerHandle) (err error) {
+	id := strconv.FormatUint(uint64(handle), 10)
+	if value, ok := client.fuzzyListenerMap.Pop(id); ok {
+		value.(*fuzzyListener).cancel()
+		return nil
+	}
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.removeListener(id)
+}
+
+// Remove the listener of id, the caller must hold client.mutex
+func (client *ConfigClient) removeListener(id string) (err error) {
+	key, ok := client.listenerKeyMap.Pop(id)
+	if !ok {
+		return errors.New("[client.CancelListener] listener not found")
+	}
+	client.closeListenerDone(id)
+	if value, ok := client.cacheMap.Get(key.(string)); ok {
+		cData := value.(cacheData)
+		cData.listeners.Remove(id)
+		logger.Infof("Cancel listener:%s DataId:%s Group:%s", id, cData.dataId, cData.group)
+		if cData.listeners.IsEmpty() {
+			client.removeCacheData(key.(string))
+		}
+	}
+	return nil
+}
+
+// Close the done channel of the listener of id to stop waiting for its ctx, the caller must hold client.mutex
+func (client *ConfigClient) closeListenerDone(id string) {
+	if done, ok := client.listenerDoneMap.Pop(id); ok {
+		close(done.(chan struct{}))
+	}
+}
+
+// Remove the cache data which has no listener, the caller must hold client.mutex
+func (client *ConfigClient) removeCacheData(key string) {
+	client.cacheMap.Remove(key)
+	remakeId := int(math.Ceil(float64(client.cacheMap.Count()) / float64(client.listenBatchSize)))
 	currentTaskCount := int(atomic.LoadInt32(&client.currentTaskCount))
 	if remakeId < currentTaskCount {
 		client.remakeCacheDataTaskId(remakeId)
 	}
-	return err
 }
 
 // Remake cache data taskId
@@ -277,7 +815,7 @@ func (client *ConfigClient) remakeCacheDataTaskId(remakeId int) {
 	for i := 0; i < remakeId; i++ {
 		count := 0
 		for _, key := range client.cacheMap.Keys() {
-			if count == perTaskConfigSize {
+			if count == client.listenBatchSize {
 				break
 			}
 			if value, ok := client.cacheMap.Get(key); ok {
@@ -290,65 +828,139 @@ func (client *ConfigClient) remakeCacheDataTaskId(remakeId int) {
 	}
 }
 
-func (client *ConfigClient) ListenConfig(param vo.ConfigParam) (err error) {
+// ListenConfig registers param.OnChange as a new listener of the dataId and group,
+// the returned handle can be used to cancel only this listener
+func (client *ConfigClient) ListenConfig(param vo.ConfigParam) (handle vo.ListenerHandle, err error) {
+	return client.ListenConfigWithContext(context.Background(), param)
+}
+
+// ListenConfigWithContext is the same as ListenConfig, the listener is cancelled when ctx is done
+func (client *ConfigClient) ListenConfigWithContext(ctx context.Context, param vo.ConfigParam) (handle vo.ListenerHandle, err error) {
 	if len(param.DataId) <= 0 {
 		err = errors.New("[client.ListenConfig] DataId can not be empty")
-		return err
+		return
 	}
 	if len(param.Group) <= 0 {
 		err = errors.New("[client.ListenConfig] Group can not be empty")
-		return err
+		return
 	}
 	clientConfig, err := client.GetClientConfig()
 	if err != nil {
 		err = errors.New("[checkConfigInfo.GetClientConfig] failed")
-		return err
+		return
 	}
 
-	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfig.NamespaceId)
+	handle = client.addListener(param, clientConfig.NamespaceId, false)
+
+	if ctx.Done() == nil || client.ctx.Err() != nil {
+		return
+	}
+	id := strconv.FormatUint(uint64(handle), 10)
+	done := make(chan struct{})
+	client.mutex.Lock()
+	// the listener may be removed already
+	if !client.listenerKeyMap.Has(id) {
+		client.mutex.Unlock()
+		return
+	}
+	client.listenerDoneMap.Set(id, done)
+	client.wg.Add(1)
+	client.mutex.Unlock()
+	go func() {
+		defer client.wg.Done()
+		select {
+		case <-ctx.Done():
+			_ = client.CancelListener(handle)
+		case <-client.ctx.Done():
+		case <-done:
+		}
+	}()
+	return
+}
+
+// Add a listener of the config, the config is added to the long polling if it is not listened yet,
+// appeared means the config is just created so the listener should be told its content
+func (client *ConfigClient) addListener(param vo.ConfigParam, tenant string, appeared bool) vo.ListenerHandle {
+	handle := vo.ListenerHandle(atomic.AddUint64(&client.listenerSeq, 1))
+	id := strconv.FormatUint(uint64(handle), 10)
+	key := util.GetConfigCacheKey(param.DataId, param.Group, tenant)
+
+	var (
+		content     string
+		md5Str      string
+		useFailover bool
+	)
+	// the appeared config is fetched from server by the long polling, so its listener gets the content
+	if !appeared && !client.cacheMap.Has(key) {
+		if content, useFailover = client.readFailoverConfig(key); !useFailover {
+			content, _ = client.configCacheStore.Get(key)
+		}
+		if len(content) > 0 {
+			md5Str = util.Md5(content)
+			var err error
+			if content, err = client.doFilter(config_filter.USAGE_NOTIFY, tenant, param.Group, param.DataId, content); err != nil {
+				logger.Errorf("[client.ListenConfig] DataId:[%s] Group:[%s] Error:[%+v]", param.DataId, param.Group, err)
+			}
+		}
+	}
+
+	client.mutex.Lock()
 	var cData cacheData
 	if v, ok := client.cacheMap.Get(key); ok {
 		cData = v.(cacheData)
 		cData.isInitializing = true
+		// the late listener has no lastMd5, so it gets the current content
+		cData.listeners.Set(id, &cacheDataListener{
+			listener:       param.OnChange,
+			eventListener:  param.OnChangeEvent,
+			healthListener: param.OnListenHealthChange,
+			configType:     util.GetConfigType(param.DataId, param.Type),
+		})
+		client.checkListenerMd5(cData)
 	} else {
-		var (
-			content string
-			md5Str  string
-		)
-		if content, _ = cache.ReadConfigFromFile(key, client.configCacheDir); len(content) > 0 {
-			md5Str = util.Md5(content)
-		}
-		listener := &cacheDataListener{
-			listener: param.OnChange,
-			lastMd5:  md5Str,
-		}
-
 		cData = cacheData{
-			isInitializing:    true,
-			dataId:            param.DataId,
-			group:             param.Group,
-			tenant:            clientConfig.NamespaceId,
-			content:           content,
-			md5:               md5Str,
-			cacheDataListener: listener,
-			taskId:            client.cacheMap.Count() / perTaskConfigSize,
+			isInitializing: true,
+			dataId:         param.DataId,
+			group:          param.Group,
+			tenant:         tenant,
+			content:        content,
+			md5:            md5Str,
+			listeners:      cache.NewConcurrentMap(),
+			taskId:         client.cacheMap.Count() / client.listenBatchSize,
+			useFailover:    useFailover,
 		}
+		cData.listeners.Set(id, &cacheDataListener{
+			listener:       param.OnChange,
+			eventListener:  param.OnChangeEvent,
+			healthListener: param.OnListenHealthChange,
+			configType:     util.GetConfigType(param.DataId, param.Type),
+			lastMd5:        md5Str,
+			lastContent:    content,
+		})
 	}
 	client.cacheMap.Set(key, cData)
-	return
+	client.listenerKeyMap.Set(id, key)
+	client.mutex.Unlock()
+	return handle
 }
 
 // Delay Scheduler
 // initialDelay the time to delay first execution
 // delay the delay between the termination of one execution and the commencement of the next
 func (client *ConfigClient) delayScheduler(t *time.Timer, delay time.Duration, taskId string, execute func() error) {
+	defer client.wg.Done()
 	for {
 		if v, ok := client.schedulerMap.Get(taskId); ok {
 			if !v.(bool) {
 				return
 			}
 		}
-		<-t.C
+		select {
+		case <-client.ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
 		d := delay
 		if err := execute(); err != nil {
 			d = executorErrDelay
@@ -361,11 +973,12 @@ func (client *ConfigClient) delayScheduler(t *time.Timer, delay time.Duration, t
 func (client *ConfigClient) listenConfigExecutor() func() error {
 	return func() error {
 		listenerSize := client.cacheMap.Count()
-		taskCount := int(math.Ceil(float64(listenerSize) / float64(perTaskConfigSize)))
+		taskCount := int(math.Ceil(float64(listenerSize) / float64(client.listenBatchSize)))
 		currentTaskCount := int(atomic.LoadInt32(&client.currentTaskCount))
 		if taskCount > currentTaskCount {
 			for i := currentTaskCount; i < taskCount; i++ {
 				client.schedulerMap.Set(strconv.Itoa(i), true)
+				client.wg.Add(1)
 				go client.delayScheduler(time.NewTimer(1*time.Millisecond), 10*time.Millisecond, strconv.Itoa(i), client.longPulling(i))
 			}
 			atomic.StoreInt32(&client.currentTaskCount, int32(taskCount))
@@ -384,26 +997,36 @@ func (client *ConfigClient) listenConfigExecutor() func() error {
 // Long polling listening configuration
 func (client *ConfigClient) longPulling(taskId int) func() error {
 	return func() error {
-		var listeningConfigs string
+		client.checkFailoverConfigs(taskId)
+		var listeningConfigs strings.Builder
+		listeningKeys := make([]string, 0)
 		initializationList := make([]cacheData, 0)
+		failoverCount := 0
 		for _, key := range client.cacheMap.Keys() {
 			if value, ok := client.cacheMap.Get(key); ok {
 				cData := value.(cacheData)
-				if cData.taskId == taskId {
+				// the failover config is not listened, otherwise the server returns it as changed at once
+				if cData.taskId == taskId && cData.useFailover {
+					failoverCount++
+				} else if cData.taskId == taskId {
 					if cData.isInitializing {
 						initializationList = append(initializationList, cData)
 					}
+					listeningKeys = append(listeningKeys, key)
+					listeningConfigs.WriteString(cData.dataId)
+					listeningConfigs.WriteString(constant.SPLIT_CONFIG_INNER)
+					listeningConfigs.WriteString(cData.group)
+					listeningConfigs.WriteString(constant.SPLIT_CONFIG_INNER)
+					listeningConfigs.WriteString(cData.md5)
 					if len(cData.tenant) > 0 {
-						listeningConfigs += cData.dataId + constant.SPLIT_CONFIG_INNER + cData.group + constant.SPLIT_CONFIG_INNER +
-							cData.md5 + constant.SPLIT_CONFIG_INNER + cData.tenant + constant.SPLIT_CONFIG
-					} else {
-						listeningConfigs += cData.dataId + constant.SPLIT_CONFIG_INNER + cData.group + constant.SPLIT_CONFIG_INNER +
-							cData.md5 + constant.SPLIT_CONFIG
+						listeningConfigs.WriteString(constant.SPLIT_CONFIG_INNER)
+						listeningConfigs.WriteString(cData.tenant)
 					}
+					listeningConfigs.WriteString(constant.SPLIT_CONFIG)
 				}
 			}
 		}
-		if len(listeningConfigs) > 0 {
+		if len(listeningKeys) > 0 {
 			clientConfig, err := client.GetClientConfig()
 			if err != nil {
 				logger.Errorf("[checkConfigInfo.GetClientConfig] err: %+v", err)
@@ -411,10 +1034,10 @@ func (client *ConfigClient) longPulling(taskId int) func() error {
 			}
 			// http get
 			params := make(map[string]string)
-			params[constant.KEY_LISTEN_CONFIGS] = listeningConfigs
+			params[constant.KEY_LISTEN_CONFIGS] = listeningConfigs.String()
 
 			var changed string
-			changedTmp, err := client.configProxy.ListenConfig(params, len(initializationList) > 0, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+			changedTmp, err := client.configProxy.ListenConfig(client.ctx, params, len(initializationList) > 0, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
 			if err == nil {
 				changed = changedTmp
 			} else {
@@ -423,24 +1046,156 @@ func (client *ConfigClient) longPulling(taskId int) func() error {
 				} else {
 					logger.Errorf("[client.ListenConfig] listen config error: %+v", err)
 				}
+				// the long polling is aborted by CloseClient
+				if client.ctx.Err() == nil {
+					client.updateListenHealth(listeningKeys, err)
+				}
 				return err
 			}
+			client.mutex.Lock()
 			for _, v := range initializationList {
-				v.isInitializing = false
-				client.cacheMap.Set(util.GetConfigCacheKey(v.dataId, v.group, v.tenant), v)
+				key := util.GetConfigCacheKey(v.dataId, v.group, v.tenant)
+				if value, ok := client.cacheMap.Get(key); ok {
+					cData := value.(cacheData)
+					cData.isInitializing = false
+					client.cacheMap.Set(key, cData)
+				}
 			}
+			client.mutex.Unlock()
+			client.updateListenHealth(listeningKeys, nil)
 			if len(strings.ToLower(strings.Trim(changed, " "))) == 0 {
 				logger.Info("[client.ListenConfig] no change")
 			} else {
 				logger.Info("[client.ListenConfig] config changed:" + changed)
 				client.callListener(changed, clientConfig.NamespaceId)
 			}
+		} else if failoverCount > 0 {
+			// there is no long polling to wait for, so the failover files are checked at intervals
+			select {
+			case <-client.ctx.Done():
+			case <-time.After(failoverCheckDelay):
+			}
 		}
 		return nil
 	}
 
 }
 
+// Update the health of the listened configs by the result of the long polling,
+// the listeners are notified when the config becomes degraded or recovers
+func (client *ConfigClient) updateListenHealth(keys []string, err error) {
+	now := time.Now()
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	for _, key := range keys {
+		value, ok := client.cacheMap.Get(key)
+		if !ok {
+			continue
+		}
+		cData := value.(cacheData)
+		degraded := cData.listenFailures >= client.listenMaxFailures
+		if err == nil {
+			cData.lastCheckTime = now
+			cData.listenFailures = 0
+		} else {
+			cData.listenFailures++
+		}
+		cData.listenError = err
+		client.cacheMap.Set(key, cData)
+		if degraded != (cData.listenFailures >= client.listenMaxFailures) {
+			health := client.listenHealth(cData)
+			if health.Degraded {
+				logger.Warnf("[client.ListenConfig] config %s is degraded after %d long polling failures", key, health.ConsecutiveFailures)
+			} else {
+				logger.Infof("[client.ListenConfig] config %s is recovered", key)
+			}
+			for _, item := range cData.listeners.Items() {
+				if healthListener := item.(*cacheDataListener).healthListener; healthListener != nil {
+					client.wg.Add(1)
+					go func() {
+						defer client.wg.Done()
+						healthListener(health)
+					}()
+				}
+			}
+		}
+	}
+}
+
+func (client *ConfigClient) listenHealth(cData cacheData) vo.ConfigListenHealth {
+	return vo.ConfigListenHealth{
+		Namespace:           cData.tenant,
+		Group:               cData.group,
+		DataId:              cData.dataId,
+		LastCheckTime:       cData.lastCheckTime,
+		ConsecutiveFailures: cData.listenFailures,
+		LastError:           cData.listenError,
+		Degraded:            cData.listenFailures >= client.listenMaxFailures,
+	}
+}
+
+// GetListenHealth returns the long polling health of all the listened configs, sorted by namespace, group and dataId
+func (client *ConfigClient) GetListenHealth() []vo.ConfigListenHealth {
+	healths := make([]vo.ConfigListenHealth, 0, client.cacheMap.Count())
+	for _, value := range client.cacheMap.Items() {
+		healths = append(healths, client.listenHealth(value.(cacheData)))
+	}
+	sort.Slice(healths, func(i, j int) bool {
+		if healths[i].Namespace != healths[j].Namespace {
+			return healths[i].Namespace < healths[j].Namespace
+		}
+		if healths[i].Group != healths[j].Group {
+			return healths[i].Group < healths[j].Group
+		}
+		return healths[i].DataId < healths[j].DataId
+	})
+	return healths
+}
+
+// Use the failover files of the listened configs instead of the server, and go back to the server when the files
+// are removed, the long polling tells the server content as changed since its md5 is different from the failover one
+func (client *ConfigClient) checkFailoverConfigs(taskId int) {
+	for _, key := range client.cacheMap.Keys() {
+		value, ok := client.cacheMap.Get(key)
+		if !ok || value.(cacheData).taskId != taskId {
+			continue
+		}
+		cData := value.(cacheData)
+		content, ok := client.readFailoverConfig(key)
+		if !ok && !cData.useFailover {
+			continue
+		}
+		md5Str := util.Md5(content)
+		if ok && cData.useFailover && cData.md5 == md5Str {
+			continue
+		}
+		var err error
+		if ok {
+			logger.Warnf("[client.checkFailoverConfigs] use failover config %s", key)
+			if content, err = client.doFilter(config_filter.USAGE_NOTIFY, cData.tenant, cData.group, cData.dataId, content); err != nil {
+				logger.Errorf("[client.checkFailoverConfigs] DataId:[%s] Group:[%s] Error:[%+v]", cData.dataId, cData.group, err)
+				continue
+			}
+		} else {
+			logger.Infof("[client.checkFailoverConfigs] failover config %s is removed, use the server", key)
+		}
+		client.mutex.Lock()
+		// the config may be cancelled while reading the file
+		if value, exist := client.cacheMap.Get(key); exist {
+			cData = value.(cacheData)
+			cData.useFailover = ok
+			if ok {
+				cData.content = content
+				cData.md5 = md5Str
+				cData.isBeta = false
+				client.checkListenerMd5(cData)
+			}
+			client.cacheMap.Set(key, cData)
+		}
+		client.mutex.Unlock()
+	}
+}
+
 // Execute the Listener callback func()
 func (client *ConfigClient) callListener(changed, tenant string) {
 	changedDecoded, _ := url.QueryUnescape(changed)
@@ -450,7 +1205,7 @@ func (client *ConfigClient) callListener(changed, tenant string) {
 		if len(attrs) >= 2 {
 			if value, ok := client.cacheMap.Get(util.GetConfigCacheKey(attrs[0], attrs[1], tenant)); ok {
 				cData := value.(cacheData)
-				content, err := client.getConfigInner(vo.ConfigParam{
+				content, source, isBeta, err := client.getConfigInner(client.ctx, vo.ConfigParam{
 					DataId: cData.dataId,
 					Group:  cData.group,
 				})
@@ -458,18 +1213,95 @@ func (client *ConfigClient) callListener(changed, tenant string) {
 					logger.Errorf("[client.getConfigInner] DataId:[%s] Group:[%s] Error:[%+v]", cData.dataId, cData.group, err)
 					continue
 				}
-				cData.content = content
-				cData.md5 = util.Md5(content)
-				if cData.md5 != cData.cacheDataListener.lastMd5 {
-					go cData.cacheDataListener.listener(tenant, attrs[1], attrs[0], cData.content)
-					cData.cacheDataListener.lastMd5 = cData.md5
-					client.cacheMap.Set(util.GetConfigCacheKey(cData.dataId, cData.group, tenant), cData)
+				key := util.GetConfigCacheKey(cData.dataId, cData.group, tenant)
+				if client.warmUpKeys[key] && source != vo.SOURCE_FAILOVER {
+					client.warmUpContents.Set(key, content)
 				}
+				// the md5 is of the content on server, while the listeners get the filtered content
+				md5Str := util.Md5(content)
+				content, err = client.doFilter(config_filter.USAGE_NOTIFY, tenant, cData.group, cData.dataId, content)
+				if err != nil {
+					logger.Errorf("[client.callListener] DataId:[%s] Group:[%s] Error:[%+v]", cData.dataId, cData.group, err)
+					continue
+				}
+				client.mutex.Lock()
+				// the config may be cancelled while getting the content
+				if _, ok := client.cacheMap.Get(key); ok {
+					cData.content = content
+					cData.md5 = md5Str
+					cData.isBeta = isBeta
+					client.cacheMap.Set(key, cData)
+					client.checkListenerMd5(cData)
+				}
+				client.mutex.Unlock()
 			}
 		}
 	}
 }
 
+// Notify the listeners whose lastMd5 is different from the cache data, the caller must hold client.mutex
+func (client *ConfigClient) checkListenerMd5(cData cacheData) {
+	if len(cData.md5) == 0 {
+		return
+	}
+	for _, item := range cData.listeners.Items() {
+		client.notifyListener(cData, item.(*cacheDataListener))
+	}
+}
+
+// Notify the listener if its lastMd5 is different from the cache data, the caller must hold client.mutex
+func (client *ConfigClient) notifyListener(cData cacheData, listener *cacheDataListener) {
+	if listener.lastMd5 == cData.md5 {
+		return
+	}
+	event := vo.ConfigChangeEvent{
+		Namespace:  cData.tenant,
+		Group:      cData.group,
+		DataId:     cData.dataId,
+		OldContent: listener.lastContent,
+		NewContent: cData.content,
+		OldMd5:     listener.lastMd5,
+		NewMd5:     cData.md5,
+		IsBeta:     cData.isBeta,
+	}
+	listener.lastMd5 = cData.md5
+	listener.lastContent = cData.content
+	if listener.listener != nil {
+		callback, content := listener.listener, cData.content
+		listener.queue.push(&client.wg, func() {
+			callback(cData.tenant, cData.group, cData.dataId, content)
+		})
+	}
+	// an empty config is not a change for the listener which has not seen any content
+	if listener.eventListener != nil && (len(event.OldContent) > 0 || len(event.NewContent) > 0) {
+		if len(event.OldContent) == 0 {
+			event.ChangeType = vo.ADDED
+		} else if len(event.NewContent) == 0 {
+			event.ChangeType = vo.DELETED
+		} else {
+			event.ChangeType = vo.MODIFIED
+		}
+		eventListener, configType := listener.eventListener, listener.configType
+		listener.queue.push(&client.wg, func() {
+			event.Diff = diffConfig(configType, event.OldContent, event.NewContent)
+			eventListener(event)
+		})
+	}
+}
+
+// CloseClient stops the listening goroutines and waits for the in-flight listener callbacks,
+// it must not be called inside a listener callback
+func (client *ConfigClient) CloseClient() {
+	client.cancel()
+	client.wg.Wait()
+	if client.configProxy.nacosServer != nil {
+		client.configProxy.nacosServer.Wait()
+	}
+	if client.configCacheStore != nil {
+		client.configCacheStore.Close()
+	}
+}
+
 func (client *ConfigClient) buildBasePath(serverConfig constant.ServerConfig) (basePath string) {
 	basePath = "http://" + serverConfig.IpAddr + ":" +
 		strconv.FormatUint(serverConfig.Port, 10) + serverConfig.ContextPath + constant.CONFIG_PATH
@@ -477,46 +1309,72 @@ func (client *ConfigClient) buildBasePath(serverConfig constant.ServerConfig) (b
 }
 
 func (client *ConfigClient) SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage, error) {
-	return client.searchConfigInner(param)
+	return client.SearchConfigWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) SearchConfigWithContext(ctx context.Context, param vo.SearchConfigParam) (*model.ConfigPage, error) {
+	return client.searchConfigInner(ctx, param)
 }
 
 func (client *ConfigClient) PublishAggr(param vo.ConfigParam) (published bool,
+	err error) {
+	return client.PublishAggrWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) PublishAggrWithContext(ctx context.Context, param vo.ConfigParam) (published bool,
 	err error) {
 	if len(param.DataId) <= 0 {
-		err = errors.New("[client.PublishAggr] param.dataId can not be empty")
+		return false, errors.New("[client.PublishAggr] param.dataId can not be empty")
 	}
 	if len(param.Group) <= 0 {
-		err = errors.New("[client.PublishAggr] param.group can not be empty")
+		return false, errors.New("[client.PublishAggr] param.group can not be empty")
 	}
 	if len(param.Content) <= 0 {
-		err = errors.New("[client.PublishAggr] param.content can not be empty")
+		return false, errors.New("[client.PublishAggr] param.content can not be empty")
 	}
 	if len(param.DatumId) <= 0 {
-		err = errors.New("[client.PublishAggr] param.DatumId can not be empty")
+		return false, errors.New("[client.PublishAggr] param.DatumId can not be empty")
 	}
 	clientConfig, _ := client.GetClientConfig()
-	return client.configProxy.PublishAggProxy(param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	return client.configProxy.PublishAggProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+}
+
+func (client *ConfigClient) RemoveAggr(param vo.ConfigParam) (removed bool,
+	err error) {
+	return client.RemoveAggrWithContext(context.Background(), param)
 }
 
-func (client *ConfigClient) RemoveAggr(param vo.ConfigParam) (published bool,
+func (client *ConfigClient) RemoveAggrWithContext(ctx context.Context, param vo.ConfigParam) (removed bool,
 	err error) {
 	if len(param.DataId) <= 0 {
-		err = errors.New("[client.DeleteAggr] param.dataId can not be empty")
+		return false, errors.New("[client.RemoveAggr] param.dataId can not be empty")
 	}
 	if len(param.Group) <= 0 {
-		err = errors.New("[client.DeleteAggr] param.group can not be empty")
-	}
-	if len(param.Content) <= 0 {
-		err = errors.New("[client.DeleteAggr] param.content can not be empty")
+		return false, errors.New("[client.RemoveAggr] param.group can not be empty")
 	}
 	if len(param.DatumId) <= 0 {
-		err = errors.New("[client.DeleteAggr] param.DatumId can not be empty")
+		return false, errors.New("[client.RemoveAggr] param.DatumId can not be empty")
 	}
 	clientConfig, _ := client.GetClientConfig()
-	return client.configProxy.DeleteAggProxy(param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	return client.configProxy.DeleteAggProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+}
+
+func (client *ConfigClient) GetAggr(param vo.ConfigParam) (content string, err error) {
+	return client.GetAggrWithContext(context.Background(), param)
+}
+
+// The server merges the datums into the config of the same dataId and group, so the merged result is read as a config
+func (client *ConfigClient) GetAggrWithContext(ctx context.Context, param vo.ConfigParam) (content string, err error) {
+	if len(param.DataId) <= 0 {
+		return "", errors.New("[client.GetAggr] param.dataId can not be empty")
+	}
+	if len(param.Group) <= 0 {
+		return "", errors.New("[client.GetAggr] param.group can not be empty")
+	}
+	return client.GetConfigWithContext(ctx, vo.ConfigParam{DataId: param.DataId, Group: param.Group})
 }
 
-func (client *ConfigClient) searchConfigInner(param vo.SearchConfigParam) (*model.ConfigPage, error) {
+func (client *ConfigClient) searchConfigInner(ctx context.Context, param vo.SearchConfigParam) (*model.ConfigPage, error) {
 	if param.Search != "accurate" && param.Search != "blur" {
 		return nil, errors.New("[client.searchConfigInner] param.search must be accurate or blur")
 	}
@@ -527,7 +1385,7 @@ func (client *ConfigClient) searchConfigInner(param vo.SearchConfigParam) (*mode
 		param.PageSize = 10
 	}
 	clientConfig, _ := client.GetClientConfig()
-	configItems, err := client.configProxy.SearchConfigProxy(param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
+	configItems, err := client.configProxy.SearchConfigProxy(ctx, param, clientConfig.NamespaceId, clientConfig.AccessKey, clientConfig.SecretKey)
 	if err != nil {
 		logger.Errorf("search config from server error:%+v ", err)
 		if _, ok := err.(*nacos_error.NacosError); ok {
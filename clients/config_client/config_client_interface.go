@@ -17,6 +17,9 @@
 package config_client
 
 import (
+	"context"
+	"io"
+
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
 )
@@ -30,32 +33,158 @@ type IConfigClient interface {
 	// tenant ==>nacos.namespace optional
 	GetConfig(param vo.ConfigParam) (string, error)
 
+	// GetConfigWithContext is the same as GetConfig, the request is aborted when ctx is done
+	GetConfigWithContext(ctx context.Context, param vo.ConfigParam) (string, error)
+
+	// GetConfigWithSource is the same as GetConfig, source tells where the content is read from:
+	// the failover file which takes priority over the server, the server, or the snapshot when the server is unavailable
+	GetConfigWithSource(param vo.ConfigParam) (content string, source vo.ConfigSource, err error)
+
+	// GetConfigWithSourceWithContext is the same as GetConfigWithSource, the request is aborted when ctx is done
+	GetConfigWithSourceWithContext(ctx context.Context, param vo.ConfigParam) (content string, source vo.ConfigSource, err error)
+
+	// GetConfigDetail use to get config with its metadata, md5 and modify time from nacos server,
+	// nil is returned if the config does not exist
+	// dataId  require
+	// group   require
+	// tenant ==>nacos.namespace optional
+	GetConfigDetail(param vo.ConfigParam) (*model.ConfigDetail, error)
+
+	// GetConfigDetailWithContext is the same as GetConfigDetail, the request is aborted when ctx is done
+	GetConfigDetailWithContext(ctx context.Context, param vo.ConfigParam) (*model.ConfigDetail, error)
+
 	// PublishConfig use to publish config to nacos server
 	// dataId  require
 	// group   require
 	// content require
+	// desc, configTags, appName, effect, schema optional
 	// tenant ==>nacos.namespace optional
 	PublishConfig(param vo.ConfigParam) (bool, error)
 
+	// PublishConfigWithContext is the same as PublishConfig, the request is aborted when ctx is done
+	PublishConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error)
+
+	// PublishConfigCAS use to publish config only when the md5 of the config on server is expectedMd5,
+	// *nacos_error.ConfigConflictError is returned when the config has been changed
+	// dataId  require
+	// group   require
+	// content require
+	// tenant ==>nacos.namespace optional
+	PublishConfigCAS(param vo.ConfigParam, expectedMd5 string) (bool, error)
+
+	// PublishConfigCASWithContext is the same as PublishConfigCAS, the request is aborted when ctx is done
+	PublishConfigCASWithContext(ctx context.Context, param vo.ConfigParam, expectedMd5 string) (bool, error)
+
+	// UpdateConfigCAS use to read the config from server, modify it and publish it by PublishConfigCAS,
+	// it retries at most maxRetries times when the config is changed concurrently.
+	// When the config does not exist, *nacos_error.ConfigNotFoundError is returned without calling modify,
+	// since the creation can not be protected by the compare and swap, create it by PublishConfig instead
+	// dataId  require
+	// group   require
+	// modify  require, it gets the current content and returns the new content
+	// tenant ==>nacos.namespace optional
+	UpdateConfigCAS(param vo.ConfigParam, maxRetries int, modify func(content string) (string, error)) error
+
+	// UpdateConfigCASWithContext is the same as UpdateConfigCAS, the requests and the retries are aborted when ctx is done
+	UpdateConfigCASWithContext(ctx context.Context, param vo.ConfigParam, maxRetries int, modify func(content string) (string, error)) error
+
+	// ListConfigHistory use to list the change history of config, the latest change comes first
+	// dataId  require
+	// group   require
+	// pageNo  option, default is 1
+	// pageSize option, default is 10
+	// tenant ==>nacos.namespace optional
+	ListConfigHistory(param vo.ConfigParam, page vo.PageParam) (*model.ConfigHistoryPage, error)
+
+	// ListConfigHistoryWithContext is the same as ListConfigHistory, the request is aborted when ctx is done
+	ListConfigHistoryWithContext(ctx context.Context, param vo.ConfigParam, page vo.PageParam) (*model.ConfigHistoryPage, error)
+
+	// GetConfigHistory use to get a history record of config by its id
+	GetConfigHistory(nid int64) (*model.ConfigHistory, error)
+
+	// GetConfigHistoryWithContext is the same as GetConfigHistory, the request is aborted when ctx is done
+	GetConfigHistoryWithContext(ctx context.Context, nid int64) (*model.ConfigHistory, error)
+
+	// RollbackConfig use to republish the content of the history record nid,
+	// the history record must belong to the config
+	// dataId  require
+	// group   require
+	// tenant ==>nacos.namespace optional
+	RollbackConfig(param vo.ConfigParam, nid int64) (bool, error)
+
+	// RollbackConfigWithContext is the same as RollbackConfig, the request is aborted when ctx is done
+	RollbackConfigWithContext(ctx context.Context, param vo.ConfigParam, nid int64) (bool, error)
+
+	// PublishConfigBeta use to publish the beta config, only the clients whose ip is in betaIps get it,
+	// the other clients keep getting the stable config until StopBeta or PublishConfig
+	// dataId  require
+	// group   require
+	// content require
+	// betaIps require
+	// tenant ==>nacos.namespace optional
+	PublishConfigBeta(param vo.ConfigParam, betaIps []string) (bool, error)
+
+	// PublishConfigBetaWithContext is the same as PublishConfigBeta, the request is aborted when ctx is done
+	PublishConfigBetaWithContext(ctx context.Context, param vo.ConfigParam, betaIps []string) (bool, error)
+
+	// GetConfigBeta use to get the beta config and its beta ips, nil is returned if the config is not in beta
+	// dataId  require
+	// group   require
+	// tenant ==>nacos.namespace optional
+	GetConfigBeta(param vo.ConfigParam) (*model.ConfigBeta, error)
+
+	// GetConfigBetaWithContext is the same as GetConfigBeta, the request is aborted when ctx is done
+	GetConfigBetaWithContext(ctx context.Context, param vo.ConfigParam) (*model.ConfigBeta, error)
+
+	// StopBeta use to delete the beta config, all the clients get the stable config again
+	// dataId  require
+	// group   require
+	// tenant ==>nacos.namespace optional
+	StopBeta(param vo.ConfigParam) (bool, error)
+
+	// StopBetaWithContext is the same as StopBeta, the request is aborted when ctx is done
+	StopBetaWithContext(ctx context.Context, param vo.ConfigParam) (bool, error)
+
 	// DeleteConfig use to delete config
 	// dataId  require
 	// group   require
 	// tenant ==>nacos.namespace optional
 	DeleteConfig(param vo.ConfigParam) (bool, error)
 
+	// DeleteConfigWithContext is the same as DeleteConfig, the request is aborted when ctx is done
+	DeleteConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error)
+
 	// ListenConfig use to listen config change,it will callback OnChange() when config change
+	// a dataId and group can have several listeners, each call returns the handle of a new listener
 	// dataId  require
 	// group   require
 	// onchange require
 	// tenant ==>nacos.namespace optional
-	ListenConfig(params vo.ConfigParam) (err error)
+	ListenConfig(params vo.ConfigParam) (handle vo.ListenerHandle, err error)
 
-	//CancelListenConfig use to cancel listen config change
+	// ListenConfigWithContext is the same as ListenConfig, the listener is cancelled when ctx is done
+	ListenConfigWithContext(ctx context.Context, params vo.ConfigParam) (handle vo.ListenerHandle, err error)
+
+	//CancelListenConfig use to cancel listen config change, all the listeners of the config are removed
 	// dataId  require
 	// group   require
 	// tenant ==>nacos.namespace optional
 	CancelListenConfig(params vo.ConfigParam) (err error)
 
+	// CancelListener use to cancel the single listener returned by ListenConfig or FuzzyListenConfig
+	CancelListener(handle vo.ListenerHandle) (err error)
+
+	// GetListenHealth use to get the long polling health of all the listened configs, a config is degraded
+	// after ClientConfig.ListenMaxFailures consecutive failures, its listeners may have the stale content
+	GetListenHealth() []vo.ConfigListenHealth
+
+	// FuzzyListenConfig use to listen all the configs matching the patterns, '*' matches any characters
+	// the matching configs are searched every IntervalMs, the appeared configs are notified as ADDED
+	// and the deleted configs are notified as DELETED
+	// dataIdPattern option
+	// groupPattern  option, but can not be empty with dataIdPattern
+	FuzzyListenConfig(param vo.FuzzyListenConfigParam) (handle vo.ListenerHandle, err error)
+
 	// SearchConfig use to search nacos config
 	// search  require search=accurate--精确搜索  search=blur--模糊搜索
 	// group   option
@@ -65,5 +194,82 @@ type IConfigClient interface {
 	// pageSize option,default is 10
 	SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage, error)
 
+	// SearchConfigWithContext is the same as SearchConfig, the request is aborted when ctx is done
+	SearchConfigWithContext(ctx context.Context, param vo.SearchConfigParam) (*model.ConfigPage, error)
+
+	// SearchConfigAll use to iterate over the configs of all the search pages, the pages are fetched
+	// in the background and the configs are iterated in order
+	// search  require search=accurate--精确搜索  search=blur--模糊搜索
+	// group   option
+	// dataId  option
+	// tenant ==>nacos.namespace optional
+	// pageSize option,default is 100
+	// concurrency option,default is 1
+	SearchConfigAll(param vo.SearchConfigAllParam) model.ConfigIterator
+
+	// SearchConfigAllWithContext is the same as SearchConfigAll, the iteration stops with ctx.Err() when ctx is done
+	SearchConfigAllWithContext(ctx context.Context, param vo.SearchConfigAllParam) model.ConfigIterator
+
+	// ExportConfigs use to export the matching configs as a zip archive which has the same layout as
+	// the one exported by nacos console, the contents are exported as stored on server with the app name,
+	// type, desc and tags of the configs
+	// search  option, default is blur, '*' matches any characters with blur
+	// group   option
+	// dataId  option
+	// tenant ==>nacos.namespace optional
+	ExportConfigs(param vo.SearchConfigParam) (io.Reader, error)
+
+	// ExportConfigsWithContext is the same as ExportConfigs, the requests are aborted when ctx is done
+	ExportConfigsWithContext(ctx context.Context, param vo.SearchConfigParam) (io.Reader, error)
+
+	// ImportConfigs use to publish the configs of the zip archive exported by ExportConfigs or nacos console,
+	// the existing configs are handled by policy, the result of every config is reported in the result
+	// archive require
+	// policy  require ABORT, SKIP or OVERWRITE
+	// tenant ==>nacos.namespace optional
+	ImportConfigs(archive io.Reader, policy vo.ImportPolicy) (*model.ConfigImportResult, error)
+
+	// ImportConfigsWithContext is the same as ImportConfigs, the requests are aborted when ctx is done
+	ImportConfigsWithContext(ctx context.Context, archive io.Reader, policy vo.ImportPolicy) (*model.ConfigImportResult, error)
+
+	// PublishAggr use to add or update a datum of the aggregated config, the server merges all the datums
+	// into the config of the same dataId and group
+	// dataId  require
+	// group   require
+	// datumId require
+	// content require
+	// tenant ==>nacos.namespace optional
 	PublishAggr(param vo.ConfigParam) (published bool, err error)
+
+	// PublishAggrWithContext is the same as PublishAggr, the request is aborted when ctx is done
+	PublishAggrWithContext(ctx context.Context, param vo.ConfigParam) (published bool, err error)
+
+	// RemoveAggr use to remove a datum of the aggregated config
+	// dataId  require
+	// group   require
+	// datumId require
+	// tenant ==>nacos.namespace optional
+	RemoveAggr(param vo.ConfigParam) (removed bool, err error)
+
+	// RemoveAggrWithContext is the same as RemoveAggr, the request is aborted when ctx is done
+	RemoveAggrWithContext(ctx context.Context, param vo.ConfigParam) (removed bool, err error)
+
+	// GetAggr use to get the merged content of all the datums of the aggregated config
+	// dataId  require
+	// group   require
+	// tenant ==>nacos.namespace optional
+	GetAggr(param vo.ConfigParam) (content string, err error)
+
+	// GetAggrWithContext is the same as GetAggr, the request is aborted when ctx is done
+	GetAggrWithContext(ctx context.Context, param vo.ConfigParam) (content string, err error)
+
+	// Ready returns true if all the ClientConfig.WarmUpConfigs are loaded from server or the snapshot into memory
+	Ready() bool
+
+	// WaitReady waits until all the ClientConfig.WarmUpConfigs are loaded, ctx.Err() is returned if ctx is done before that,
+	// it can be used to gate the traffic by the readiness probe
+	WaitReady(ctx context.Context) error
+
+	// CloseClient use to stop the listening goroutines and wait for the in-flight listener callbacks, then close the snapshot store
+	CloseClient()
 }
@@ -17,10 +17,19 @@
 package config_client
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
 	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -28,13 +37,19 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/clients/cache"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/clients/nacos_client"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/config_filter"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/constant"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/http_agent"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/nacos_error"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/mock"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
 )
 
+// the mock must be regenerated when IConfigClient changes
+var _ IConfigClient = (*mock.MockIConfigClient)(nil)
+
 var goVersion = runtime.Version()
 
 var clientConfigTest = constant.ClientConfig{
@@ -196,7 +211,7 @@ func Test_PublishConfigWithErrorResponse(t *testing.T) {
 
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
 	clientHttp := createConfigClientHttpTest(mockHttpAgent)
-	mockHttpAgent.EXPECT().Request(gomock.Eq(http.MethodPost),
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(clientConfigTest.TimeoutMs),
@@ -213,7 +228,7 @@ func Test_PublishConfigWithErrorResponse_200(t *testing.T) {
 
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
 	clientHttp := createConfigClientHttpTest(mockHttpAgent)
-	mockHttpAgent.EXPECT().Request(gomock.Eq(http.MethodPost),
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(clientConfigTest.TimeoutMs),
@@ -224,6 +239,27 @@ func Test_PublishConfigWithErrorResponse_200(t *testing.T) {
 	assert.True(t, !success)
 }
 
+func Test_PublishConfigWithCanceledContext(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	ctx, cancel := context.WithCancel(context.Background())
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Eq(ctx), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.AssignableToTypeOf(http.Header{}),
+		gomock.Eq(clientConfigTest.TimeoutMs),
+		gomock.Eq(localConfigMapTest),
+	).Times(1).DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		cancel()
+		return nil, ctx.Err()
+	})
+	success, err := clientHttp.PublishConfigWithContext(ctx, localConfigTest)
+	assert.NotNil(t, err)
+	assert.True(t, !success)
+}
+
 // DeleteConfig
 
 func Test_DeleteConfig(t *testing.T) {
@@ -250,7 +286,7 @@ func Test_DeleteConfigWithErrorResponse_200(t *testing.T) {
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
 	clientHttp := createConfigClientHttpTest(mockHttpAgent)
 
-	mockHttpAgent.EXPECT().Request(gomock.Eq(http.MethodDelete),
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodDelete),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(clientConfigTest.TimeoutMs),
@@ -268,7 +304,7 @@ func Test_DeleteConfigWithErrorResponse_401(t *testing.T) {
 	mockHttpAgent := mock.NewMockIHttpAgent(controller)
 	clientHttp := createConfigClientHttpTest(mockHttpAgent)
 
-	mockHttpAgent.EXPECT().Request(gomock.Eq(http.MethodDelete),
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodDelete),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(clientConfigTest.TimeoutMs),
@@ -302,11 +338,11 @@ func TestListen(t *testing.T) {
 	// ListenConfig
 	t.Run("TestListenConfig", func(t *testing.T) {
 		key := util.GetConfigCacheKey(localConfigTest.DataId, localConfigTest.Group, clientConfigTest.NamespaceId)
-		cache.WriteConfigToFile(key, client.configCacheDir, "")
+		client.configCacheStore.Put(key, "")
 		var err error
 		var success bool
 		ch := make(chan string)
-		err = client.ListenConfig(vo.ConfigParam{
+		_, err = client.ListenConfig(vo.ConfigParam{
 			DataId: localConfigTest.DataId,
 			Group:  localConfigTest.Group,
 			OnChange: func(namespace, group, dataId, data string) {
@@ -336,18 +372,18 @@ func TestListen(t *testing.T) {
 			OnChange: func(namespace, group, dataId, data string) {
 			},
 		}
-		err := client.ListenConfig(listenConfigParam)
+		_, err := client.ListenConfig(listenConfigParam)
 		assert.Error(t, err)
 	})
 	// ListenConfig no change
 	t.Run("TestListenConfigNoChange", func(t *testing.T) {
 		key := util.GetConfigCacheKey(configNoChangeKey, localConfigTest.Group, clientConfigTest.NamespaceId)
-		cache.WriteConfigToFile(key, client.configCacheDir, localConfigTest.Content)
+		client.configCacheStore.Put(key, localConfigTest.Content)
 		var err error
 		var success bool
 		var content string
 
-		err = client.ListenConfig(vo.ConfigParam{
+		_, err = client.ListenConfig(vo.ConfigParam{
 			DataId: configNoChangeKey,
 			Group:  localConfigTest.Group,
 			OnChange: func(namespace, group, dataId, data string) {
@@ -376,7 +412,7 @@ func TestListen(t *testing.T) {
 			},
 		}
 		key := util.GetConfigCacheKey(listenConfigParam.DataId, listenConfigParam.Group, clientConfigTest.NamespaceId)
-		cache.WriteConfigToFile(key, client.configCacheDir, "")
+		client.configCacheStore.Put(key, "")
 		client.ListenConfig(listenConfigParam)
 
 		nc := nacos_client.NacosClient{}
@@ -412,7 +448,7 @@ func TestListen(t *testing.T) {
 			},
 		}
 		key := util.GetConfigCacheKey(listenConfigParam.DataId, listenConfigParam.Group, clientConfigTest.NamespaceId)
-		cache.WriteConfigToFile(key, client.configCacheDir, "")
+		client.configCacheStore.Put(key, "")
 		client.ListenConfig(listenConfigParam)
 
 		nc := nacos_client.NacosClient{}
@@ -439,6 +475,1241 @@ func TestListen(t *testing.T) {
 	})
 }
 
+func TestListenConfigMultipleListeners(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs/listener"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).Return(http_agent.FakeHttpResponse(200, "hello"), nil)
+
+	param := vo.ConfigParam{DataId: "multiple-listeners", Group: "group"}
+	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfigTest.NamespaceId)
+	clientHttp.configCacheStore.Put(key, "")
+
+	ch1 := make(chan string, 1)
+	param.OnChange = func(namespace, group, dataId, data string) {
+		ch1 <- data
+	}
+	handle1, err := clientHttp.ListenConfig(param)
+	assert.Nil(t, err)
+	clientHttp.callListener(param.DataId+"%02"+param.Group+"%01", clientConfigTest.NamespaceId)
+	select {
+	case c := <-ch1:
+		assert.Equal(t, "hello", c)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	// the late listener gets the current content
+	ch2 := make(chan string, 1)
+	param.OnChange = func(namespace, group, dataId, data string) {
+		ch2 <- data
+	}
+	handle2, err := clientHttp.ListenConfig(param)
+	assert.Nil(t, err)
+	assert.NotEqual(t, handle1, handle2)
+	select {
+	case c := <-ch2:
+		assert.Equal(t, "hello", c)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	// cancel one listener keeps the others
+	assert.Nil(t, clientHttp.CancelListener(handle1))
+	assert.NotNil(t, clientHttp.CancelListener(handle1))
+	value, ok := clientHttp.cacheMap.Get(key)
+	assert.True(t, ok)
+	assert.Equal(t, 1, value.(cacheData).listeners.Count())
+
+	assert.Nil(t, clientHttp.CancelListener(handle2))
+	assert.False(t, clientHttp.cacheMap.Has(key))
+}
+
+func TestListenConfigChangeEvent(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs/listener"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).Return(http_agent.FakeHttpResponse(200, "a=1\nb=3\nc=4"), nil)
+
+	param := vo.ConfigParam{DataId: "change-event", Group: "group", Type: vo.PROPERTIES}
+	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfigTest.NamespaceId)
+	clientHttp.configCacheStore.Put(key, "a=1\nb=2")
+
+	ch := make(chan vo.ConfigChangeEvent, 1)
+	param.OnChangeEvent = func(event vo.ConfigChangeEvent) {
+		ch <- event
+	}
+	_, err := clientHttp.ListenConfig(param)
+	assert.Nil(t, err)
+	clientHttp.callListener(param.DataId+"%02"+param.Group+"%01", clientConfigTest.NamespaceId)
+	select {
+	case event := <-ch:
+		assert.Equal(t, vo.MODIFIED, event.ChangeType)
+		assert.Equal(t, "a=1\nb=2", event.OldContent)
+		assert.Equal(t, "a=1\nb=3\nc=4", event.NewContent)
+		assert.Equal(t, util.Md5("a=1\nb=2"), event.OldMd5)
+		assert.Equal(t, util.Md5("a=1\nb=3\nc=4"), event.NewMd5)
+		assert.Equal(t, []vo.ConfigKeyChange{{Key: "c", NewValue: "4"}}, event.Diff.Added)
+		assert.Equal(t, []vo.ConfigKeyChange{{Key: "b", OldValue: "2", NewValue: "3"}}, event.Diff.Modified)
+		assert.Empty(t, event.Diff.Removed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestFuzzyListenConfig(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs/listener"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	var pageItems atomic.Value
+	pageItems.Store(`[{"dataId":"fuzzy-a","group":"group"}]`)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		if params["search"] == "blur" {
+			assert.Equal(t, "fuzzy-*", params["dataId"])
+			return http_agent.FakeHttpResponse(200, `{"pagesAvailable":1,"pageItems":`+pageItems.Load().(string)+`}`), nil
+		}
+		return http_agent.FakeHttpResponse(200, "b-content"), nil
+	})
+
+	keyA := util.GetConfigCacheKey("fuzzy-a", "group", clientConfigTest.NamespaceId)
+	keyB := util.GetConfigCacheKey("fuzzy-b", "group", clientConfigTest.NamespaceId)
+	clientHttp.configCacheStore.Put(keyA, "a-content")
+	clientHttp.configCacheStore.Put(keyB, "")
+	ch := make(chan vo.ConfigChangeEvent, 2)
+	handle, err := clientHttp.FuzzyListenConfig(vo.FuzzyListenConfigParam{
+		DataIdPattern: "fuzzy-*",
+		IntervalMs:    10,
+		OnChangeEvent: func(event vo.ConfigChangeEvent) {
+			ch <- event
+		},
+	})
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool { return clientHttp.cacheMap.Has(keyA) }, 5*time.Second, 10*time.Millisecond)
+
+	// the appeared config is notified as added
+	pageItems.Store(`[{"dataId":"fuzzy-a","group":"group"},{"dataId":"fuzzy-b","group":"group"}]`)
+	assert.Eventually(t, func() bool { return clientHttp.cacheMap.Has(keyB) }, 5*time.Second, 10*time.Millisecond)
+	clientHttp.callListener("fuzzy-b%02group%01", clientConfigTest.NamespaceId)
+	select {
+	case event := <-ch:
+		assert.Equal(t, vo.ADDED, event.ChangeType)
+		assert.Equal(t, "fuzzy-b", event.DataId)
+		assert.Equal(t, "b-content", event.NewContent)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	// the deleted config is notified as deleted
+	pageItems.Store(`[{"dataId":"fuzzy-b","group":"group"}]`)
+	select {
+	case event := <-ch:
+		assert.Equal(t, vo.DELETED, event.ChangeType)
+		assert.Equal(t, "fuzzy-a", event.DataId)
+		assert.Equal(t, "a-content", event.OldContent)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+	assert.Eventually(t, func() bool { return !clientHttp.cacheMap.Has(keyA) }, 5*time.Second, 10*time.Millisecond)
+
+	assert.Nil(t, clientHttp.CancelListener(handle))
+	assert.Eventually(t, func() bool { return !clientHttp.cacheMap.Has(keyB) }, 5*time.Second, 10*time.Millisecond)
+}
+
+type prefixFilter struct{}
+
+func (filter *prefixFilter) Name() string {
+	return "prefix"
+}
+
+func (filter *prefixFilter) DoFilter(config *config_filter.ConfigContext) error {
+	if config.Usage == config_filter.USAGE_PUBLISH {
+		config.Content = "enc:" + config.Content
+		return nil
+	}
+	if !strings.HasPrefix(config.Content, "enc:") {
+		return errors.New("invalid content")
+	}
+	config.Content = strings.TrimPrefix(config.Content, "enc:")
+	return nil
+}
+
+func TestConfigFilters(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	nc := nacos_client.NacosClient{}
+	_ = nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	config := clientConfigTest
+	config.ConfigFilters = []config_filter.ConfigFilter{&prefixFilter{}}
+	_ = nc.SetClientConfig(config)
+	_ = nc.SetHttpAgent(mockHttpAgent)
+	clientHttp, _ := NewConfigClient(&nc)
+	defer clientHttp.CloseClient()
+
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		assert.Equal(t, "enc:hello", params["content"])
+		return http_agent.FakeHttpResponse(200, "true"), nil
+	})
+	success, err := clientHttp.PublishConfig(vo.ConfigParam{DataId: "filter", Group: "group", Content: "hello"})
+	assert.Nil(t, err)
+	assert.True(t, success)
+
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).Return(http_agent.FakeHttpResponse(200, "enc:hello"), nil)
+	content, err := clientHttp.GetConfig(vo.ConfigParam{DataId: "filter", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", content)
+
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).Return(http_agent.FakeHttpResponse(200, "hello"), nil)
+	_, err = clientHttp.GetConfig(vo.ConfigParam{DataId: "filter", Group: "group"})
+	assert.NotNil(t, err)
+}
+
+func TestPublishConfigCAS(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		assert.Equal(t, util.Md5("old"), params["casMd5"])
+		return http_agent.FakeHttpResponse(500, "Cas publish fail, server md5 may have changed."), nil
+	})
+
+	success, err := clientHttp.PublishConfigCAS(vo.ConfigParam{DataId: "cas", Group: "group", Content: "new"}, util.Md5("old"))
+	assert.False(t, success)
+	conflictErr, ok := err.(*nacos_error.ConfigConflictError)
+	assert.True(t, ok)
+	assert.Equal(t, "cas", conflictErr.DataId)
+	assert.Equal(t, util.Md5("old"), conflictErr.ExpectedMd5)
+
+	_, err = clientHttp.PublishConfigCAS(vo.ConfigParam{DataId: "cas", Group: "group", Content: "new"}, "")
+	assert.NotNil(t, err)
+}
+
+func TestUpdateConfigCAS(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	serverContent := "1"
+	published := 0
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		if serverContent == "" {
+			return http_agent.FakeHttpResponse(404, "config data not exist"), nil
+		}
+		content := serverContent
+		// another client changes the config after the first read
+		serverContent = "2"
+		return http_agent.FakeHttpResponse(200, content), nil
+	})
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		if params["casMd5"] != util.Md5(serverContent) {
+			return http_agent.FakeHttpResponse(409, "conflict"), nil
+		}
+		published++
+		serverContent = params["content"]
+		return http_agent.FakeHttpResponse(200, "true"), nil
+	})
+
+	increase := func(content string) (string, error) {
+		n, err := strconv.Atoi(content)
+		return strconv.Itoa(n + 1), err
+	}
+	err := clientHttp.UpdateConfigCAS(vo.ConfigParam{DataId: "cas", Group: "group"}, 3, increase)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, "3", serverContent)
+
+	// no retry left
+	serverContent = "1"
+	err = clientHttp.UpdateConfigCAS(vo.ConfigParam{DataId: "cas", Group: "group"}, 0, increase)
+	_, ok := err.(*nacos_error.ConfigConflictError)
+	assert.True(t, ok)
+
+	// the caller can abort the retries
+	ctx, cancel := context.WithCancel(context.Background())
+	err = clientHttp.UpdateConfigCASWithContext(ctx, vo.ConfigParam{DataId: "cas", Group: "group"}, 100, func(content string) (string, error) {
+		cancel()
+		return increase(content)
+	})
+	assert.NotNil(t, err)
+
+	// the config which does not exist is not created
+	serverContent, published = "", 0
+	err = clientHttp.UpdateConfigCAS(vo.ConfigParam{DataId: "cas", Group: "group"}, 3, increase)
+	_, ok = err.(*nacos_error.ConfigNotFoundError)
+	assert.True(t, ok)
+	assert.Equal(t, 0, published)
+}
+
+func TestPublishConfigWithMetadata(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		assert.Equal(t, "order service", params["desc"])
+		assert.Equal(t, "order,prod", params["config_tags"])
+		assert.Equal(t, "order", params["appName"])
+		assert.Equal(t, "online", params["effect"])
+		assert.Equal(t, "{}", params["schema"])
+		return http_agent.FakeHttpResponse(200, "true"), nil
+	})
+	success, err := clientHttp.PublishConfig(vo.ConfigParam{
+		DataId:     "metadata",
+		Group:      "group",
+		Content:    "hello",
+		Desc:       "order service",
+		ConfigTags: "order,prod",
+		AppName:    "order",
+		Effect:     "online",
+		Schema:     "{}",
+	})
+	assert.Nil(t, err)
+	assert.True(t, success)
+}
+
+func TestGetConfigDetail(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		assert.Equal(t, "all", params["show"])
+		return http_agent.FakeHttpResponse(200, `{"id":"1","dataId":"metadata","group":"group","content":"hello",`+
+			`"md5":"5d41402abc4b2a76b9719d911017c592","appName":"order","type":"text","desc":"order service",`+
+			`"configTags":"order,prod","createTime":1600000000000,"modifyTime":1600000001000,"encryptedDataKey":""}`), nil
+	})
+	detail, err := clientHttp.GetConfigDetail(vo.ConfigParam{DataId: "metadata", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", detail.Content)
+	assert.Equal(t, util.Md5("hello"), detail.Md5)
+	assert.Equal(t, "order service", detail.Desc)
+	assert.Equal(t, "order,prod", detail.ConfigTags)
+	assert.Equal(t, int64(1600000001000), detail.ModifyTime)
+
+	_, err = clientHttp.GetConfigDetail(vo.ConfigParam{DataId: "metadata"})
+	assert.NotNil(t, err)
+}
+
+const configHistoryJson = `{"id":"42","lastId":-1,"dataId":"history","group":"group","tenant":"","appName":"order",` +
+	`"md5":"5d41402abc4b2a76b9719d911017c592","content":"hello","srcIp":"10.0.0.1","srcUser":"nacos","opType":"U",` +
+	`"createdTime":"2020-09-13T12:26:40.000+0000","lastModifiedTime":1600000000000}`
+
+func TestListConfigHistory(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/history"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		assert.Equal(t, "accurate", params["search"])
+		assert.Equal(t, "history", params["dataId"])
+		assert.Equal(t, "1", params["pageNo"])
+		assert.Equal(t, "10", params["pageSize"])
+		return http_agent.FakeHttpResponse(200, `{"totalCount":1,"pageNumber":1,"pagesAvailable":1,"pageItems":[`+
+			configHistoryJson+`]}`), nil
+	})
+	page, err := clientHttp.ListConfigHistory(vo.ConfigParam{DataId: "history", Group: "group"}, vo.PageParam{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, page.TotalCount)
+	assert.Equal(t, 1, len(page.PageItems))
+	item := page.PageItems[0]
+	assert.Equal(t, int64(42), item.Id)
+	assert.Equal(t, "hello", item.Content)
+	assert.Equal(t, "10.0.0.1", item.SrcIp)
+	assert.Equal(t, "nacos", item.SrcUser)
+	assert.Equal(t, model.CONFIG_OP_UPDATE, item.OpType)
+	assert.Equal(t, int64(1600000000000), item.CreatedTime.UnixNano()/int64(time.Millisecond))
+	assert.Equal(t, int64(1600000000000), item.LastModifiedTime.UnixNano()/int64(time.Millisecond))
+
+	_, err = clientHttp.ListConfigHistory(vo.ConfigParam{DataId: "history"}, vo.PageParam{})
+	assert.NotNil(t, err)
+}
+
+func TestRollbackConfig(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/history"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(2).DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		assert.Equal(t, "42", params["nid"])
+		return http_agent.FakeHttpResponse(200, configHistoryJson), nil
+	})
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		assert.Equal(t, "hello", params["content"])
+		assert.Equal(t, "order", params["appName"])
+		return http_agent.FakeHttpResponse(200, "true"), nil
+	})
+	success, err := clientHttp.RollbackConfig(vo.ConfigParam{DataId: "history", Group: "group"}, 42)
+	assert.Nil(t, err)
+	assert.True(t, success)
+
+	success, err = clientHttp.RollbackConfig(vo.ConfigParam{DataId: "other", Group: "group"}, 42)
+	assert.NotNil(t, err)
+	assert.False(t, success)
+}
+
+func TestPublishConfigBeta(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		assert.Equal(t, []string{"10.0.0.1,10.0.0.2"}, header["betaIps"])
+		assert.Equal(t, "beta", params["content"])
+		return http_agent.FakeHttpResponse(200, "true"), nil
+	})
+	success, err := clientHttp.PublishConfigBeta(vo.ConfigParam{DataId: "beta", Group: "group", Content: "beta"},
+		[]string{"10.0.0.1", "10.0.0.2"})
+	assert.Nil(t, err)
+	assert.True(t, success)
+
+	_, err = clientHttp.PublishConfigBeta(vo.ConfigParam{DataId: "beta", Group: "group", Content: "beta"}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestGetConfigBetaAndStopBeta(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		assert.Equal(t, "true", params["beta"])
+		return http_agent.FakeHttpResponse(200, `{"code":200,"message":"query beta ok","data":{"id":"1","dataId":"beta",`+
+			`"group":"group","content":"beta","md5":"987bcab01b929eb2c07877b224215c92","betaIps":"10.0.0.1,10.0.0.2"}}`), nil
+	})
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodDelete),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		assert.Equal(t, "true", params["beta"])
+		return http_agent.FakeHttpResponse(200, `{"code":200,"message":"stop beta ok","data":true}`), nil
+	})
+	param := vo.ConfigParam{DataId: "beta", Group: "group"}
+	configBeta, err := clientHttp.GetConfigBeta(param)
+	assert.Nil(t, err)
+	assert.Equal(t, "beta", configBeta.Content)
+	assert.Equal(t, util.Md5("beta"), configBeta.Md5)
+	assert.Equal(t, "10.0.0.1,10.0.0.2", configBeta.BetaIps)
+
+	stopped, err := clientHttp.StopBeta(param)
+	assert.Nil(t, err)
+	assert.True(t, stopped)
+}
+
+func TestListenConfigBeta(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs/listener"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	response := http_agent.FakeHttpResponse(200, "beta")
+	response.Header.Set("isBeta", "true")
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).Return(response, nil)
+
+	param := vo.ConfigParam{DataId: "listen-beta", Group: "group"}
+	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfigTest.NamespaceId)
+	clientHttp.configCacheStore.Put(key, "stable")
+
+	ch := make(chan vo.ConfigChangeEvent, 1)
+	param.OnChangeEvent = func(event vo.ConfigChangeEvent) {
+		ch <- event
+	}
+	_, err := clientHttp.ListenConfig(param)
+	assert.Nil(t, err)
+	clientHttp.callListener(param.DataId+"%02"+param.Group+"%01", clientConfigTest.NamespaceId)
+	select {
+	case event := <-ch:
+		assert.Equal(t, "beta", event.NewContent)
+		assert.True(t, event.IsBeta)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func mockSearchPages(mockHttpAgent *mock.MockIHttpAgent, pageCount int, errorPage int) {
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		pageNo, _ := strconv.Atoi(params["pageNo"])
+		if pageNo == errorPage {
+			return http_agent.FakeHttpResponse(500, "server error"), nil
+		}
+		items := make([]string, 0, 2)
+		for i := 0; i < 2; i++ {
+			items = append(items, fmt.Sprintf(`{"dataId":"config-%d","group":"group"}`, (pageNo-1)*2+i))
+		}
+		return http_agent.FakeHttpResponse(200, fmt.Sprintf(`{"totalCount":%d,"pageNumber":%d,"pagesAvailable":%d,"pageItems":[%s]}`,
+			pageCount*2, pageNo, pageCount, strings.Join(items, ","))), nil
+	})
+}
+
+func TestSearchConfigAll(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	mockSearchPages(mockHttpAgent, 5, 0)
+
+	param := vo.SearchConfigAllParam{
+		SearchConfigParam: vo.SearchConfigParam{Search: "blur", Group: "group", PageSize: 2},
+		Concurrency:       3,
+	}
+	it := clientHttp.SearchConfigAll(param)
+	var dataIds []string
+	for it.Next() {
+		dataIds = append(dataIds, it.Item().DataId)
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, 10, len(dataIds))
+	for i, dataId := range dataIds {
+		assert.Equal(t, "config-"+strconv.Itoa(i), dataId)
+	}
+
+	// stop early
+	it = clientHttp.SearchConfigAll(param)
+	assert.True(t, it.Next())
+	it.Close()
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Err())
+}
+
+func TestSearchConfigAllWithError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	mockSearchPages(mockHttpAgent, 3, 2)
+
+	it := clientHttp.SearchConfigAll(vo.SearchConfigAllParam{
+		SearchConfigParam: vo.SearchConfigParam{Search: "blur", Group: "group", PageSize: 2},
+	})
+	count := 0
+	for it.Next() {
+		count++
+	}
+	assert.Equal(t, 2, count)
+	assert.NotNil(t, it.Err())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = clientHttp.SearchConfigAllWithContext(ctx, vo.SearchConfigAllParam{
+		SearchConfigParam: vo.SearchConfigParam{Search: "blur", Group: "group"},
+	})
+	assert.False(t, it.Next())
+	assert.Equal(t, context.Canceled, it.Err())
+}
+
+func TestExportAndImportConfigs(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	published := map[string]map[string]string{}
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		if params["search"] == "blur" {
+			return http_agent.FakeHttpResponse(200, `{"totalCount":2,"pageNumber":1,"pagesAvailable":1,"pageItems":[`+
+				`{"dataId":"order.yaml","group":"group","content":"a: 1","appName":"order"},`+
+				`{"dataId":"user","group":"group","content":"b=2"}]}`), nil
+		}
+		if params["show"] == "all" {
+			if params["dataId"] == "order.yaml" {
+				return http_agent.FakeHttpResponse(200, `{"dataId":"order.yaml","group":"group","content":"a: 1","appName":"order",`+
+					`"type":"yaml","desc":" the order\\config\nof shop ","configTags":"a,b"}`), nil
+			}
+			return http_agent.FakeHttpResponse(404, "config data not exist"), nil
+		}
+		// only user exists on server when importing
+		if params["dataId"] == "user" {
+			return http_agent.FakeHttpResponse(200, `{"totalCount":1,"pageNumber":1,"pagesAvailable":1,"pageItems":[]}`), nil
+		}
+		return http_agent.FakeHttpResponse(200, `{"totalCount":0,"pageNumber":1,"pagesAvailable":0,"pageItems":[]}`), nil
+	})
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		published[params["dataId"]] = params
+		return http_agent.FakeHttpResponse(200, "true"), nil
+	})
+
+	archive, err := clientHttp.ExportConfigs(vo.SearchConfigParam{Group: "group"})
+	assert.Nil(t, err)
+	data, err := ioutil.ReadAll(archive)
+	assert.Nil(t, err)
+
+	result, err := clientHttp.ImportConfigs(bytes.NewReader(data), vo.ABORT)
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, result.FailCount)
+	assert.Equal(t, model.CONFIG_IMPORT_ABORTED, result.Items[0].Status)
+	assert.Equal(t, model.CONFIG_IMPORT_FAILED, result.Items[1].Status)
+	assert.Empty(t, published)
+
+	result, err = clientHttp.ImportConfigs(bytes.NewReader(data), vo.SKIP)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, 1, result.SkipCount)
+	assert.Equal(t, model.ConfigImportItem{DataId: "order.yaml", Group: "group", Status: model.CONFIG_IMPORT_SUCCESS}, result.Items[0])
+	assert.Equal(t, model.CONFIG_IMPORT_SKIPPED, result.Items[1].Status)
+	assert.Equal(t, "a: 1", published["order.yaml"]["content"])
+	assert.Equal(t, "order", published["order.yaml"]["appName"])
+	assert.Equal(t, "yaml", published["order.yaml"]["type"])
+	assert.Equal(t, " the order\\config\nof shop ", published["order.yaml"]["desc"])
+	assert.Equal(t, "a,b", published["order.yaml"]["config_tags"])
+
+	result, err = clientHttp.ImportConfigs(bytes.NewReader(data), vo.OVERWRITE)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Equal(t, "b=2", published["user"]["content"])
+	_, ok := published["user"]["type"]
+	assert.False(t, ok)
+
+	_, err = clientHttp.ImportConfigs(strings.NewReader("not a zip"), vo.OVERWRITE)
+	assert.NotNil(t, err)
+}
+
+func TestImportCipherConfigToAnotherNamespace(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	// the fake server stores the configs by namespace
+	var mutex sync.Mutex
+	server := map[string]map[string]string{}
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if server[params["tenant"]] == nil {
+			server[params["tenant"]] = map[string]string{}
+		}
+		server[params["tenant"]][params["group"]+"/"+params["dataId"]] = params["content"]
+		return http_agent.FakeHttpResponse(200, "true"), nil
+	})
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		configs := server[params["tenant"]]
+		if params["search"] == "blur" {
+			page := model.ConfigPage{TotalCount: len(configs), PageNumber: 1, PagesAvailable: 1}
+			for key, content := range configs {
+				names := strings.SplitN(key, "/", 2)
+				page.PageItems = append(page.PageItems, model.ConfigItem{Group: names[0], DataId: names[1], Content: content})
+			}
+			data, _ := json.Marshal(page)
+			return http_agent.FakeHttpResponse(200, string(data)), nil
+		}
+		content, ok := configs[params["group"]+"/"+params["dataId"]]
+		if !ok {
+			return http_agent.FakeHttpResponse(404, "config data not exist"), nil
+		}
+		if params["show"] == "all" {
+			data, _ := json.Marshal(model.ConfigDetail{Group: params["group"], DataId: params["dataId"], Content: content})
+			return http_agent.FakeHttpResponse(200, string(data)), nil
+		}
+		return http_agent.FakeHttpResponse(200, content), nil
+	})
+	keyRing, err := config_filter.ParseKeyRing([]byte(`{"primary":"v1","keys":{"v1":"MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="}}`))
+	assert.Nil(t, err)
+	newClient := func(namespaceId string) *ConfigClient {
+		clientConfig := clientConfigTest
+		clientConfig.NamespaceId = namespaceId
+		nc := nacos_client.NacosClient{}
+		nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+		nc.SetClientConfig(clientConfig)
+		nc.SetHttpAgent(mockHttpAgent)
+		configClient, _ := NewConfigClient(&nc)
+		configClient.configFilters = append(configClient.configFilters, config_filter.NewAESFilter(keyRing))
+		return configClient
+	}
+	source, target := newClient(""), newClient("target")
+	defer source.CloseClient()
+	defer target.CloseClient()
+
+	param := vo.ConfigParam{DataId: "cipher-db", Group: "group", Content: "password=123"}
+	_, err = source.PublishConfig(param)
+	assert.Nil(t, err)
+	assert.NotEqual(t, "password=123", server[""]["group/cipher-db"])
+	archive, err := source.ExportConfigs(vo.SearchConfigParam{Group: "group"})
+	assert.Nil(t, err)
+	result, err := target.ImportConfigs(archive, vo.OVERWRITE)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.SuccessCount)
+	content, err := target.GetConfig(vo.ConfigParam{DataId: "cipher-db", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "password=123", content)
+}
+
+func TestAggrConfig(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	var methods []string
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/datum.do"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(2).DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		assert.Equal(t, "datum-1", params["datumId"])
+		methods = append(methods, params["method"])
+		return http_agent.FakeHttpResponse(200, "true"), nil
+	})
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).Return(http_agent.FakeHttpResponse(200, "a\nb"), nil)
+
+	param := vo.ConfigParam{DataId: "aggr", Group: "group", DatumId: "datum-1", Content: "a"}
+	published, err := clientHttp.PublishAggr(param)
+	assert.Nil(t, err)
+	assert.True(t, published)
+	content, err := clientHttp.GetAggr(param)
+	assert.Nil(t, err)
+	assert.Equal(t, "a\nb", content)
+	removed, err := clientHttp.RemoveAggr(vo.ConfigParam{DataId: "aggr", Group: "group", DatumId: "datum-1"})
+	assert.Nil(t, err)
+	assert.True(t, removed)
+	assert.Equal(t, []string{"addDatum", "deleteDatum"}, methods)
+
+	// the invalid params are not sent to server
+	_, err = clientHttp.PublishAggr(vo.ConfigParam{DataId: "aggr", Group: "group", Content: "a"})
+	assert.NotNil(t, err)
+	_, err = clientHttp.RemoveAggr(vo.ConfigParam{DataId: "aggr", DatumId: "datum-1"})
+	assert.NotNil(t, err)
+}
+
+func TestGetConfigWithSource(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	clientHttp.configFailoverDir = t.TempDir()
+	clientHttp.configCacheStore = cache.NewMemoryCacheStore()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).Return(http_agent.FakeHttpResponse(200, "server"), nil)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(constant.REQUEST_DOMAIN_RETRY_TIME).Return(http_agent.FakeHttpResponse(500, "server error"), nil)
+
+	param := vo.ConfigParam{DataId: "source", Group: "group"}
+	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfigTest.NamespaceId)
+	cache.WriteConfigToFile(key, clientHttp.configFailoverDir, "failover")
+	content, source, err := clientHttp.GetConfigWithSource(param)
+	assert.Nil(t, err)
+	assert.Equal(t, "failover", content)
+	assert.Equal(t, vo.SOURCE_FAILOVER, source)
+
+	assert.Nil(t, os.Remove(cache.GetFileName(key, clientHttp.configFailoverDir)))
+	content, source, err = clientHttp.GetConfigWithSource(param)
+	assert.Nil(t, err)
+	assert.Equal(t, "server", content)
+	assert.Equal(t, vo.SOURCE_SERVER, source)
+
+	// the server content is kept in the snapshot
+	content, source, err = clientHttp.GetConfigWithSource(param)
+	assert.Nil(t, err)
+	assert.Equal(t, "server", content)
+	assert.Equal(t, vo.SOURCE_SNAPSHOT, source)
+}
+
+func TestListenConfigFailover(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	clientHttp.configFailoverDir = t.TempDir()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs/listener"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	param := vo.ConfigParam{DataId: "listen-failover", Group: "group"}
+	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfigTest.NamespaceId)
+	clientHttp.configCacheStore.Put(key, "snapshot")
+	ch := make(chan vo.ConfigChangeEvent, 1)
+	param.OnChangeEvent = func(event vo.ConfigChangeEvent) {
+		ch <- event
+	}
+	_, err := clientHttp.ListenConfig(param)
+	assert.Nil(t, err)
+
+	cache.WriteConfigToFile(key, clientHttp.configFailoverDir, "failover")
+	clientHttp.checkFailoverConfigs(0)
+	select {
+	case event := <-ch:
+		assert.Equal(t, "snapshot", event.OldContent)
+		assert.Equal(t, "failover", event.NewContent)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+	value, _ := clientHttp.cacheMap.Get(key)
+	assert.True(t, value.(cacheData).useFailover)
+
+	assert.Nil(t, os.Remove(cache.GetFileName(key, clientHttp.configFailoverDir)))
+	clientHttp.checkFailoverConfigs(0)
+	value, _ = clientHttp.cacheMap.Get(key)
+	assert.False(t, value.(cacheData).useFailover)
+	// the md5 of the failover content makes the long polling fetch the server content
+	assert.Equal(t, util.Md5("failover"), value.(cacheData).md5)
+}
+
+func TestGetConfigWithMemoryCacheStore(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientConfig := clientConfigTest
+	clientConfig.CacheDir = t.TempDir()
+	clientConfig.CacheStoreType = constant.CACHE_STORE_MEMORY
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mockHttpAgent)
+	clientHttp, err := NewConfigClient(&nc)
+	assert.Nil(t, err)
+	defer clientHttp.CloseClient()
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1).Return(http_agent.FakeHttpResponse(200, "server"), nil)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(constant.REQUEST_DOMAIN_RETRY_TIME).Return(http_agent.FakeHttpResponse(500, "server error"), nil)
+
+	param := vo.ConfigParam{DataId: "memory", Group: "group"}
+	content, source, err := clientHttp.GetConfigWithSource(param)
+	assert.Nil(t, err)
+	assert.Equal(t, "server", content)
+	assert.Equal(t, vo.SOURCE_SERVER, source)
+
+	// the snapshot is kept in memory when the server is unavailable
+	content, source, err = clientHttp.GetConfigWithSource(param)
+	assert.Nil(t, err)
+	assert.Equal(t, "server", content)
+	assert.Equal(t, vo.SOURCE_SNAPSHOT, source)
+	_, err = os.Stat(clientConfig.CacheDir + string(os.PathSeparator) + "config")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWarmUpConfigs(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	// the first load fails as there is no snapshot, and the retry succeeds
+	var requests int32
+	serverContent := atomic.Value{}
+	serverContent.Store("warm")
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodGet),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		if atomic.AddInt32(&requests, 1) <= constant.REQUEST_DOMAIN_RETRY_TIME {
+			return http_agent.FakeHttpResponse(500, "server error"), nil
+		}
+		return http_agent.FakeHttpResponse(200, serverContent.Load().(string)), nil
+	})
+	// the warm-up config is listened, but it's not changed
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs/listener"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	clientConfig := clientConfigTest
+	clientConfig.CacheStoreType = constant.CACHE_STORE_MEMORY
+	clientConfig.WarmUpConfigs = []constant.WarmUpConfig{{DataId: "warm", Group: "group"}}
+	clientConfig.WarmUpTimeoutMs = 100
+	nc := nacos_client.NacosClient{}
+	nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	nc.SetClientConfig(clientConfig)
+	nc.SetHttpAgent(mockHttpAgent)
+	clientHttp, err := NewConfigClient(&nc)
+	assert.Nil(t, err)
+	defer clientHttp.CloseClient()
+	assert.False(t, clientHttp.Ready())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, clientHttp.WaitReady(ctx))
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(t, clientHttp.WaitReady(ctx))
+	assert.True(t, clientHttp.Ready())
+	content, err := clientHttp.configCacheStore.Get(util.GetConfigCacheKey("warm", "group", clientConfig.NamespaceId))
+	assert.Nil(t, err)
+	assert.Equal(t, "warm", content)
+
+	// the warm-up config is served from memory, and updated by the long polling
+	loaded := atomic.LoadInt32(&requests)
+	content, source, err := clientHttp.GetConfigWithSource(vo.ConfigParam{DataId: "warm", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "warm", content)
+	assert.Equal(t, vo.SOURCE_MEMORY, source)
+	assert.Equal(t, loaded, atomic.LoadInt32(&requests))
+	serverContent.Store("warm2")
+	clientHttp.callListener("warm%02group%01", clientConfig.NamespaceId)
+	content, source, err = clientHttp.GetConfigWithSource(vo.ConfigParam{DataId: "warm", Group: "group"})
+	assert.Nil(t, err)
+	assert.Equal(t, "warm2", content)
+	assert.Equal(t, vo.SOURCE_MEMORY, source)
+
+	// the invalid client config is refused before the background work is started
+	clientConfig.WarmUpConfigs = []constant.WarmUpConfig{{DataId: "warm"}}
+	nc.SetClientConfig(clientConfig)
+	invalidClient, err := NewConfigClient(&nc)
+	assert.NotNil(t, err)
+	assert.False(t, invalidClient.schedulerMap.Has("root"))
+	assert.Nil(t, invalidClient.configProxy.nacosServer)
+
+	clientConfig.WarmUpConfigs = nil
+	clientConfig.OpenKMS = true
+	clientConfig.KeyRingEnv = "NACOS_KEY_RING"
+	nc.SetClientConfig(clientConfig)
+	invalidClient, err = NewConfigClient(&nc)
+	assert.EqualError(t, err, "[client.NewConfigClient] OpenKMS and the local key ring can not be both enabled")
+	assert.False(t, invalidClient.schedulerMap.Has("root"))
+}
+
+func TestListenConfigHealth(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	defer clientHttp.CloseClient()
+	listening := make(chan string, 1)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs/listener"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		select {
+		case listening <- params[constant.KEY_LISTEN_CONFIGS]:
+		default:
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	param := vo.ConfigParam{DataId: "listen-health", Group: "group"}
+	ch := make(chan vo.ConfigListenHealth, 1)
+	param.OnListenHealthChange = func(health vo.ConfigListenHealth) {
+		ch <- health
+	}
+	_, err := clientHttp.ListenConfig(param)
+	assert.Nil(t, err)
+	select {
+	case configs := <-listening:
+		assert.Equal(t, "listen-health"+constant.SPLIT_CONFIG_INNER+"group"+constant.SPLIT_CONFIG_INNER+constant.SPLIT_CONFIG, configs)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfigTest.NamespaceId)
+	for i := 0; i < listenMaxFailures; i++ {
+		clientHttp.updateListenHealth([]string{key}, errors.New("listen failed"))
+	}
+	select {
+	case health := <-ch:
+		assert.Equal(t, "listen-health", health.DataId)
+		assert.True(t, health.Degraded)
+		assert.Equal(t, listenMaxFailures, health.ConsecutiveFailures)
+		assert.NotNil(t, health.LastError)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+	healths := clientHttp.GetListenHealth()
+	assert.Equal(t, 1, len(healths))
+	assert.True(t, healths[0].Degraded)
+	assert.True(t, healths[0].LastCheckTime.IsZero())
+
+	// the listener is notified again only when the config recovers
+	clientHttp.updateListenHealth([]string{key}, errors.New("listen failed"))
+	clientHttp.updateListenHealth([]string{key}, nil)
+	select {
+	case health := <-ch:
+		assert.False(t, health.Degraded)
+		assert.Equal(t, 0, health.ConsecutiveFailures)
+		assert.Nil(t, health.LastError)
+		assert.False(t, health.LastCheckTime.IsZero())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestListenConfigWithContextStopsWaiting(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs/listener"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	param := vo.ConfigParam{DataId: "listen-with-context", Group: "group", OnChange: func(namespace, group, dataId, data string) {}}
+	key := util.GetConfigCacheKey(param.DataId, param.Group, clientConfigTest.NamespaceId)
+	clientHttp.configCacheStore.Put(key, "")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the waiting goroutine stops when the listener is removed by the handle
+	handle, err := clientHttp.ListenConfigWithContext(ctx, param)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, clientHttp.listenerDoneMap.Count())
+	assert.Nil(t, clientHttp.CancelListener(handle))
+	assert.Equal(t, 0, clientHttp.listenerDoneMap.Count())
+
+	// or by CancelListenConfig
+	_, err = clientHttp.ListenConfigWithContext(ctx, param)
+	assert.Nil(t, err)
+	assert.Nil(t, clientHttp.CancelListenConfig(param))
+	assert.Equal(t, 0, clientHttp.listenerDoneMap.Count())
+
+	// the listener is removed when ctx is done
+	listenCtx, listenCancel := context.WithCancel(context.Background())
+	_, err = clientHttp.ListenConfigWithContext(listenCtx, param)
+	assert.Nil(t, err)
+	listenCancel()
+	assert.Eventually(t, func() bool {
+		return !clientHttp.cacheMap.Has(key)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// CloseClient waits for the goroutine of a listener which is still listening
+	_, err = clientHttp.ListenConfigWithContext(ctx, param)
+	assert.Nil(t, err)
+	closed := make(chan struct{})
+	go func() {
+		clientHttp.CloseClient()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("CloseClient timeout")
+	}
+}
+
+func TestPublishAndDeleteConfigValidation(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	// no request is expected, the invalid params are refused before the filters and the proxy
+	clientHttp := createConfigClientHttpTest(mock.NewMockIHttpAgent(controller))
+	defer clientHttp.CloseClient()
+	_, err := clientHttp.PublishConfig(vo.ConfigParam{DataId: dataIdKey, Group: "group"})
+	assert.EqualError(t, err, "[client.PublishConfig] param.content can not be empty")
+	_, err = clientHttp.PublishConfig(vo.ConfigParam{DataId: dataIdKey, Content: "content"})
+	assert.EqualError(t, err, "[client.PublishConfig] param.group can not be empty")
+	deleted, err := clientHttp.DeleteConfig(vo.ConfigParam{Group: "group"})
+	assert.EqualError(t, err, "[client.DeleteConfig] param.dataId can not be empty")
+	assert.False(t, deleted)
+}
+
+func TestListenerNotifiedInOrder(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	clientHttp := createConfigClientHttpTest(mock.NewMockIHttpAgent(controller))
+	var received, events []string
+	listener := &cacheDataListener{
+		listener: func(namespace, group, dataId, data string) {
+			// the slow listener does not get the later change first
+			time.Sleep(time.Millisecond)
+			received = append(received, data)
+		},
+		eventListener: func(event vo.ConfigChangeEvent) {
+			events = append(events, event.NewContent)
+		},
+	}
+	var expected []string
+	cData := cacheData{dataId: "order", group: "group"}
+	for i := 0; i < 10; i++ {
+		cData.content = strconv.Itoa(i)
+		cData.md5 = util.Md5(cData.content)
+		expected = append(expected, cData.content)
+		clientHttp.mutex.Lock()
+		clientHttp.notifyListener(cData, listener)
+		clientHttp.mutex.Unlock()
+	}
+	clientHttp.CloseClient()
+	assert.Equal(t, expected, received)
+	assert.Equal(t, expected, events)
+}
+
+func TestCloseClient(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockHttpAgent := mock.NewMockIHttpAgent(controller)
+	clientHttp := createConfigClientHttpTest(mockHttpAgent)
+	mockHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq(http.MethodPost),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/cs/configs/listener"),
+		gomock.Any(), gomock.Any(), gomock.Any(),
+	).AnyTimes().DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	_, err := clientHttp.ListenConfig(vo.ConfigParam{
+		DataId:   "close",
+		Group:    "group",
+		OnChange: func(namespace, group, dataId, data string) {},
+	})
+	assert.Nil(t, err)
+	// wait for the long polling request
+	time.Sleep(time.Second)
+
+	closed := make(chan struct{})
+	go func() {
+		clientHttp.CloseClient()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("close client timeout")
+	}
+}
+
 func TestGetConfigWithSpecialSymbol(t *testing.T) {
 	contentStr := "hello world!!@#$%^&&*()"
 	success, err := client.PublishConfig(vo.ConfigParam{
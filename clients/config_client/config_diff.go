@@ -0,0 +1,121 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
+)
+
+// Build the key level diff of the two contents, nil is returned if the config type is not
+// json, yaml or properties, or any of the contents can not be parsed
+func diffConfig(configType vo.ConfigType, oldContent, newContent string) *vo.ConfigDiff {
+	oldKeys, err := flattenConfig(configType, oldContent)
+	if err != nil {
+		return nil
+	}
+	newKeys, err := flattenConfig(configType, newContent)
+	if err != nil {
+		return nil
+	}
+	diff := &vo.ConfigDiff{}
+	for key, newValue := range newKeys {
+		oldValue, ok := oldKeys[key]
+		if !ok {
+			diff.Added = append(diff.Added, vo.ConfigKeyChange{Key: key, NewValue: newValue})
+		} else if oldValue != newValue {
+			diff.Modified = append(diff.Modified, vo.ConfigKeyChange{Key: key, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	for key, oldValue := range oldKeys {
+		if _, ok := newKeys[key]; !ok {
+			diff.Removed = append(diff.Removed, vo.ConfigKeyChange{Key: key, OldValue: oldValue})
+		}
+	}
+	sortKeyChanges(diff.Added)
+	sortKeyChanges(diff.Removed)
+	sortKeyChanges(diff.Modified)
+	return diff
+}
+
+func sortKeyChanges(changes []vo.ConfigKeyChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+}
+
+// Flatten the content into key value pairs, the nested keys are joined by '.' and the array index is [i]
+func flattenConfig(configType vo.ConfigType, content string) (map[string]string, error) {
+	result := make(map[string]string)
+	if len(strings.TrimSpace(content)) == 0 {
+		return result, nil
+	}
+	var value interface{}
+	switch configType {
+	case vo.PROPERTIES:
+		return util.ParseProperties(content)
+	case vo.JSON:
+		decoder := json.NewDecoder(strings.NewReader(content))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+	case vo.YAML:
+		if err := yaml.Unmarshal([]byte(content), &value); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config type:%s", configType)
+	}
+	flattenValue("", value, result)
+	return result, nil
+}
+
+func flattenValue(prefix string, value interface{}, result map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			flattenValue(joinKey(prefix, key), item, result)
+		}
+	case map[interface{}]interface{}:
+		for key, item := range v {
+			flattenValue(joinKey(prefix, fmt.Sprint(key)), item, result)
+		}
+	case []interface{}:
+		for i, item := range v {
+			flattenValue(fmt.Sprintf("%s[%d]", prefix, i), item, result)
+		}
+	case nil:
+		result[prefix] = ""
+	default:
+		result[prefix] = fmt.Sprint(v)
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if len(prefix) == 0 {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
+)
+
+func TestDiffConfig(t *testing.T) {
+	diff := diffConfig(vo.JSON, `{"a":1,"b":{"c":"x","d":[1,2]}}`, `{"a":2,"b":{"c":"x","d":[1]},"e":true}`)
+	assert.Equal(t, &vo.ConfigDiff{
+		Added:    []vo.ConfigKeyChange{{Key: "e", NewValue: "true"}},
+		Removed:  []vo.ConfigKeyChange{{Key: "b.d[1]", OldValue: "2"}},
+		Modified: []vo.ConfigKeyChange{{Key: "a", OldValue: "1", NewValue: "2"}},
+	}, diff)
+
+	diff = diffConfig(vo.YAML, "a:\n  b: 1\n", "a:\n  b: 2\n")
+	assert.Equal(t, []vo.ConfigKeyChange{{Key: "a.b", OldValue: "1", NewValue: "2"}}, diff.Modified)
+
+	diff = diffConfig(vo.PROPERTIES, "", "a=1")
+	assert.Equal(t, []vo.ConfigKeyChange{{Key: "a", NewValue: "1"}}, diff.Added)
+
+	assert.Nil(t, diffConfig(vo.JSON, `{"a":1}`, `{"a":`))
+	assert.Nil(t, diffConfig(vo.TEXT, "a", "b"))
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
+)
+
+const searchAllPageSize = 100
+
+type pageResult struct {
+	page *model.ConfigPage
+	err  error
+}
+
+type configIterator struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	// the results of the pages in order, each page is fetched by its own goroutine
+	pages  chan chan pageResult
+	items  []model.ConfigItem
+	index  int
+	item   model.ConfigItem
+	err    error
+	closed bool
+}
+
+func (client *ConfigClient) SearchConfigAll(param vo.SearchConfigAllParam) model.ConfigIterator {
+	return client.SearchConfigAllWithContext(context.Background(), param)
+}
+
+func (client *ConfigClient) SearchConfigAllWithContext(ctx context.Context, param vo.SearchConfigAllParam) model.ConfigIterator {
+	if param.PageSize <= 0 {
+		param.PageSize = searchAllPageSize
+	}
+	if param.Concurrency <= 0 {
+		param.Concurrency = 1
+	}
+	it := &configIterator{
+		// the page being consumed is not in the channel, so at most Concurrency pages are fetched at a time
+		pages: make(chan chan pageResult, param.Concurrency-1),
+	}
+	it.ctx, it.cancel = context.WithCancel(ctx)
+	go it.fetchPages(client, param)
+	return it
+}
+
+// Fetch the first page to know the count of pages, then fetch the others concurrently
+func (it *configIterator) fetchPages(client *ConfigClient, param vo.SearchConfigAllParam) {
+	defer close(it.pages)
+	searchParam := param.SearchConfigParam
+	searchParam.PageNo = 1
+	first := make(chan pageResult, 1)
+	page, err := client.searchConfigInner(it.ctx, searchParam)
+	first <- pageResult{page: page, err: err}
+	if !it.sendPage(first) || err != nil || page == nil {
+		return
+	}
+	for pageNo := 2; pageNo <= page.PagesAvailable; pageNo++ {
+		result := make(chan pageResult, 1)
+		if !it.sendPage(result) {
+			return
+		}
+		searchParam.PageNo = pageNo
+		go func(searchParam vo.SearchConfigParam) {
+			page, err := client.searchConfigInner(it.ctx, searchParam)
+			result <- pageResult{page: page, err: err}
+		}(searchParam)
+	}
+}
+
+func (it *configIterator) sendPage(result chan pageResult) bool {
+	select {
+	case it.pages <- result:
+		return true
+	case <-it.ctx.Done():
+		return false
+	}
+}
+
+func (it *configIterator) Next() bool {
+	if it.closed {
+		return false
+	}
+	for it.index >= len(it.items) {
+		if it.err != nil {
+			return false
+		}
+		select {
+		case result, ok := <-it.pages:
+			if !ok {
+				it.Close()
+				return false
+			}
+			pageResult := <-result
+			if pageResult.err != nil {
+				it.err = pageResult.err
+				it.Close()
+				return false
+			}
+			// the configs may be deleted during the iteration, so the last pages can be nil or empty
+			if pageResult.page != nil {
+				it.items, it.index = pageResult.page.PageItems, 0
+			}
+		case <-it.ctx.Done():
+			// the iterator is not closed, so the parent context is done
+			it.err = it.ctx.Err()
+			it.Close()
+			return false
+		}
+	}
+	it.item = it.items[it.index]
+	it.index++
+	return true
+}
+
+func (it *configIterator) Item() model.ConfigItem {
+	return it.item
+}
+
+func (it *configIterator) Err() error {
+	return it.err
+}
+
+func (it *configIterator) Close() {
+	it.closed = true
+	it.cancel()
+}
+
+// Search the configs of all the pages
+func (client *ConfigClient) searchAllConfig(ctx context.Context, param vo.SearchConfigParam) ([]model.ConfigItem, error) {
+	var items []model.ConfigItem
+	it := client.SearchConfigAllWithContext(ctx, vo.SearchConfigAllParam{SearchConfigParam: param})
+	defer it.Close()
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
@@ -17,6 +17,7 @@
 package config_client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -26,6 +27,7 @@ import (
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/constant"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/http_agent"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/nacos_error"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/nacos_server"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
@@ -37,10 +39,10 @@ type ConfigProxy struct {
 	clientConfig constant.ClientConfig
 }
 
-func NewConfigProxy(serverConfig []constant.ServerConfig, clientConfig constant.ClientConfig, httpAgent http_agent.IHttpAgent) (ConfigProxy, error) {
+func NewConfigProxy(ctx context.Context, serverConfig []constant.ServerConfig, clientConfig constant.ClientConfig, httpAgent http_agent.IHttpAgent) (ConfigProxy, error) {
 	proxy := ConfigProxy{}
 	var err error
-	proxy.nacosServer, err = nacos_server.NewNacosServer(serverConfig, clientConfig, httpAgent, clientConfig.TimeoutMs, clientConfig.Endpoint)
+	proxy.nacosServer, err = nacos_server.NewNacosServer(ctx, serverConfig, clientConfig, httpAgent, clientConfig.TimeoutMs, clientConfig.Endpoint)
 	proxy.clientConfig = clientConfig
 	return proxy, err
 
@@ -50,7 +52,14 @@ func (cp *ConfigProxy) GetServerList() []constant.ServerConfig {
 	return cp.nacosServer.GetServerList()
 }
 
-func (cp *ConfigProxy) GetConfigProxy(param vo.ConfigParam, tenant, accessKey, secretKey string) (string, error) {
+func (cp *ConfigProxy) GetConfigProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (string, error) {
+	result, _, err := cp.GetConfigWithBetaFlagProxy(ctx, param, tenant, accessKey, secretKey)
+	return result, err
+}
+
+// GetConfigWithBetaFlagProxy is the same as GetConfigProxy, isBeta is true if the server returns the beta content to this client
+func (cp *ConfigProxy) GetConfigWithBetaFlagProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (content string,
+	isBeta bool, err error) {
 	params := util.TransformObject2Param(param)
 	if len(tenant) > 0 {
 		params["tenant"] = tenant
@@ -60,11 +69,127 @@ func (cp *ConfigProxy) GetConfigProxy(param vo.ConfigParam, tenant, accessKey, s
 	headers[constant.KEY_ACCESS_KEY] = accessKey
 	headers[constant.KEY_SECRET_KEY] = secretKey
 
-	result, err := cp.nacosServer.ReqConfigApi(constant.CONFIG_PATH, params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
-	return result, err
+	result, responseHeader, err := cp.nacosServer.ReqConfigApiWithResponseHeader(ctx, constant.CONFIG_PATH, params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+	if err != nil {
+		return "", false, err
+	}
+	isBeta, _ = strconv.ParseBool(responseHeader.Get(constant.KEY_IS_BETA))
+	return result, isBeta, nil
+}
+
+func (cp *ConfigProxy) GetConfigBetaProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (*model.ConfigBeta, error) {
+	params := map[string]string{
+		"dataId": param.DataId,
+		"group":  param.Group,
+		"beta":   "true",
+	}
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+
+	var headers = map[string]string{}
+	headers[constant.KEY_ACCESS_KEY] = accessKey
+	headers[constant.KEY_SECRET_KEY] = secretKey
+
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_PATH, params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+	if err != nil {
+		return nil, err
+	}
+	var restResult struct {
+		Code    int               `json:"code"`
+		Message string            `json:"message"`
+		Data    *model.ConfigBeta `json:"data"`
+	}
+	err = json.Unmarshal([]byte(result), &restResult)
+	if err != nil {
+		return nil, err
+	}
+	if restResult.Code != http.StatusOK {
+		return nil, errors.New("[client.GetConfigBeta] get beta config failed:" + restResult.Message)
+	}
+	return restResult.Data, nil
+}
+
+func (cp *ConfigProxy) PublishConfigBetaProxy(ctx context.Context, param vo.ConfigParam, betaIps []string, tenant, accessKey, secretKey string) (bool, error) {
+	params := util.TransformObject2Param(param)
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+
+	var headers = map[string]string{}
+	headers["accessKey"] = accessKey
+	headers["secretKey"] = secretKey
+	headers[constant.KEY_BETA_IPS] = strings.Join(betaIps, ",")
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_PATH, params, headers, http.MethodPost, cp.clientConfig.TimeoutMs)
+	if err != nil {
+		return false, errors.New("[client.PublishConfigBeta] publish beta config failed:" + err.Error())
+	}
+	if strings.ToLower(strings.Trim(result, " ")) == "true" {
+		return true, nil
+	} else {
+		return false, errors.New("[client.PublishConfigBeta] publish beta config failed:" + result)
+	}
+}
+
+func (cp *ConfigProxy) StopBetaProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
+	params := map[string]string{
+		"dataId": param.DataId,
+		"group":  param.Group,
+		"beta":   "true",
+	}
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+
+	var headers = map[string]string{}
+	headers["accessKey"] = accessKey
+	headers["secretKey"] = secretKey
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_PATH, params, headers, http.MethodDelete, cp.clientConfig.TimeoutMs)
+	if err != nil {
+		return false, errors.New("[client.StopBeta] stop beta config failed:" + err.Error())
+	}
+	var restResult struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    bool   `json:"data"`
+	}
+	err = json.Unmarshal([]byte(result), &restResult)
+	if err != nil {
+		return false, errors.New("[client.StopBeta] stop beta config failed:" + result)
+	}
+	if restResult.Code != http.StatusOK || !restResult.Data {
+		return false, errors.New("[client.StopBeta] stop beta config failed:" + restResult.Message)
+	}
+	return true, nil
+}
+
+func (cp *ConfigProxy) GetConfigDetailProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (*model.ConfigDetail, error) {
+	params := map[string]string{
+		"dataId": param.DataId,
+		"group":  param.Group,
+		"show":   "all",
+	}
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+
+	var headers = map[string]string{}
+	headers[constant.KEY_ACCESS_KEY] = accessKey
+	headers[constant.KEY_SECRET_KEY] = secretKey
+
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_PATH, params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+	if err != nil {
+		return nil, err
+	}
+	var configDetail model.ConfigDetail
+	err = json.Unmarshal([]byte(result), &configDetail)
+	if err != nil {
+		return nil, err
+	}
+	return &configDetail, nil
 }
 
-func (cp *ConfigProxy) SearchConfigProxy(param vo.SearchConfigParam, tenant, accessKey, secretKey string) (*model.ConfigPage, error) {
+func (cp *ConfigProxy) SearchConfigProxy(ctx context.Context, param vo.SearchConfigParam, tenant, accessKey, secretKey string) (*model.ConfigPage, error) {
 	params := util.TransformObject2Param(param)
 	if len(tenant) > 0 {
 		params["tenant"] = tenant
@@ -78,7 +203,7 @@ func (cp *ConfigProxy) SearchConfigProxy(param vo.SearchConfigParam, tenant, acc
 	var headers = map[string]string{}
 	headers["accessKey"] = accessKey
 	headers["secretKey"] = secretKey
-	result, err := cp.nacosServer.ReqConfigApi(constant.CONFIG_PATH, params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_PATH, params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +214,7 @@ func (cp *ConfigProxy) SearchConfigProxy(param vo.SearchConfigParam, tenant, acc
 	}
 	return &configPage, nil
 }
-func (cp *ConfigProxy) PublishConfigProxy(param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
+func (cp *ConfigProxy) PublishConfigProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
 	params := util.TransformObject2Param(param)
 	if len(tenant) > 0 {
 		params["tenant"] = tenant
@@ -98,7 +223,7 @@ func (cp *ConfigProxy) PublishConfigProxy(param vo.ConfigParam, tenant, accessKe
 	var headers = map[string]string{}
 	headers["accessKey"] = accessKey
 	headers["secretKey"] = secretKey
-	result, err := cp.nacosServer.ReqConfigApi(constant.CONFIG_PATH, params, headers, http.MethodPost, cp.clientConfig.TimeoutMs)
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_PATH, params, headers, http.MethodPost, cp.clientConfig.TimeoutMs)
 	if err != nil {
 		return false, errors.New("[client.PublishConfig] publish config failed:" + err.Error())
 	}
@@ -109,7 +234,81 @@ func (cp *ConfigProxy) PublishConfigProxy(param vo.ConfigParam, tenant, accessKe
 	}
 }
 
-func (cp *ConfigProxy) PublishAggProxy(param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
+func (cp *ConfigProxy) PublishConfigCASProxy(ctx context.Context, param vo.ConfigParam, casMd5, tenant, accessKey, secretKey string) (bool, error) {
+	params := util.TransformObject2Param(param)
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+	params["casMd5"] = casMd5
+
+	var headers = map[string]string{}
+	headers["accessKey"] = accessKey
+	headers["secretKey"] = secretKey
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_PATH, params, headers, http.MethodPost, cp.clientConfig.TimeoutMs)
+	if err != nil {
+		if nacosErr, ok := err.(*nacos_error.NacosError); ok && isCasConflict(nacosErr) {
+			return false, &nacos_error.ConfigConflictError{DataId: param.DataId, Group: param.Group, Tenant: tenant, ExpectedMd5: casMd5}
+		}
+		return false, errors.New("[client.PublishConfigCAS] publish config failed:" + err.Error())
+	}
+	if strings.ToLower(strings.Trim(result, " ")) == "true" {
+		return true, nil
+	} else {
+		return false, errors.New("[client.PublishConfigCAS] publish config failed:" + result)
+	}
+}
+
+// The server rejects the cas publish with 409 or 500 with the message "Cas publish fail"
+func isCasConflict(err *nacos_error.NacosError) bool {
+	return err.ErrorCode() == "409" || strings.Contains(err.Error(), "Cas publish fail")
+}
+
+func (cp *ConfigProxy) ListConfigHistoryProxy(ctx context.Context, param vo.ConfigParam, page vo.PageParam, tenant, accessKey, secretKey string) (*model.ConfigHistoryPage, error) {
+	params := util.TransformObject2Param(page)
+	params["search"] = "accurate"
+	params["dataId"] = param.DataId
+	params["group"] = param.Group
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+	var headers = map[string]string{}
+	headers["accessKey"] = accessKey
+	headers["secretKey"] = secretKey
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_HISTORY_PATH, params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+	if err != nil {
+		return nil, err
+	}
+	var historyPage model.ConfigHistoryPage
+	err = json.Unmarshal([]byte(result), &historyPage)
+	if err != nil {
+		return nil, err
+	}
+	return &historyPage, nil
+}
+
+func (cp *ConfigProxy) GetConfigHistoryProxy(ctx context.Context, nid int64, tenant, accessKey, secretKey string) (*model.ConfigHistory, error) {
+	params := map[string]string{
+		"nid": strconv.FormatInt(nid, 10),
+	}
+	if len(tenant) > 0 {
+		params["tenant"] = tenant
+	}
+	var headers = map[string]string{}
+	headers["accessKey"] = accessKey
+	headers["secretKey"] = secretKey
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_HISTORY_PATH, params, headers, http.MethodGet, cp.clientConfig.TimeoutMs)
+	if err != nil {
+		return nil, err
+	}
+	var history model.ConfigHistory
+	err = json.Unmarshal([]byte(result), &history)
+	if err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+func (cp *ConfigProxy) PublishAggProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
 	params := util.TransformObject2Param(param)
 	if len(tenant) > 0 {
 		params["tenant"] = tenant
@@ -118,14 +317,14 @@ func (cp *ConfigProxy) PublishAggProxy(param vo.ConfigParam, tenant, accessKey,
 	var headers = map[string]string{}
 	headers["accessKey"] = accessKey
 	headers["secretKey"] = secretKey
-	_, err := cp.nacosServer.ReqConfigApi(constant.CONFIG_AGG_PATH, params, headers, http.MethodPost, cp.clientConfig.TimeoutMs)
+	_, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_AGG_PATH, params, headers, http.MethodPost, cp.clientConfig.TimeoutMs)
 	if err != nil {
 		return false, errors.New("[client.PublishAggProxy] publish agg failed:" + err.Error())
 	}
 	return true, nil
 }
 
-func (cp *ConfigProxy) DeleteAggProxy(param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
+func (cp *ConfigProxy) DeleteAggProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
 	params := util.TransformObject2Param(param)
 	if len(tenant) > 0 {
 		params["tenant"] = tenant
@@ -134,14 +333,14 @@ func (cp *ConfigProxy) DeleteAggProxy(param vo.ConfigParam, tenant, accessKey, s
 	var headers = map[string]string{}
 	headers["accessKey"] = accessKey
 	headers["secretKey"] = secretKey
-	_, err := cp.nacosServer.ReqConfigApi(constant.CONFIG_AGG_PATH, params, headers, http.MethodPost, cp.clientConfig.TimeoutMs)
+	_, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_AGG_PATH, params, headers, http.MethodPost, cp.clientConfig.TimeoutMs)
 	if err != nil {
 		return false, errors.New("[client.DeleteAggProxy] delete agg failed:" + err.Error())
 	}
 	return true, nil
 }
 
-func (cp *ConfigProxy) DeleteConfigProxy(param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
+func (cp *ConfigProxy) DeleteConfigProxy(ctx context.Context, param vo.ConfigParam, tenant, accessKey, secretKey string) (bool, error) {
 	params := util.TransformObject2Param(param)
 	if len(tenant) > 0 {
 		params["tenant"] = tenant
@@ -149,7 +348,7 @@ func (cp *ConfigProxy) DeleteConfigProxy(param vo.ConfigParam, tenant, accessKey
 	var headers = map[string]string{}
 	headers["accessKey"] = accessKey
 	headers["secretKey"] = secretKey
-	result, err := cp.nacosServer.ReqConfigApi(constant.CONFIG_PATH, params, headers, http.MethodDelete, cp.clientConfig.TimeoutMs)
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_PATH, params, headers, http.MethodDelete, cp.clientConfig.TimeoutMs)
 	if err != nil {
 		return false, errors.New("[client.DeleteConfig] deleted config failed:" + err.Error())
 	}
@@ -160,7 +359,7 @@ func (cp *ConfigProxy) DeleteConfigProxy(param vo.ConfigParam, tenant, accessKey
 	}
 }
 
-func (cp *ConfigProxy) ListenConfig(params map[string]string, isInitializing bool, tenant, accessKey, secretKey string) (string, error) {
+func (cp *ConfigProxy) ListenConfig(ctx context.Context, params map[string]string, isInitializing bool, tenant, accessKey, secretKey string) (string, error) {
 	//fixed at 30000ms，avoid frequent request on the server
 	var listenInterval uint64 = 30000
 	headers := map[string]string{
@@ -181,6 +380,6 @@ func (cp *ConfigProxy) ListenConfig(params map[string]string, isInitializing boo
 	// In order to prevent the server from handling the delay of the client's long task,
 	// increase the client's read timeout to avoid this problem.
 	timeout := listenInterval + listenInterval/10
-	result, err := cp.nacosServer.ReqConfigApi(constant.CONFIG_LISTEN_PATH, params, headers, http.MethodPost, timeout)
+	result, err := cp.nacosServer.ReqConfigApiWithContext(ctx, constant.CONFIG_LISTEN_PATH, params, headers, http.MethodPost, timeout)
 	return result, err
 }
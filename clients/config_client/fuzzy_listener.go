@@ -0,0 +1,153 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_client
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
+)
+
+const (
+	fuzzyListenIntervalMs = 30000
+)
+
+type fuzzyListener struct {
+	param  vo.FuzzyListenConfigParam
+	cancel context.CancelFunc
+	// the listeners of the matching configs keyed by cache key, it is only used by the searching goroutine
+	matched map[string]vo.ListenerHandle
+}
+
+// FuzzyListenConfig searches the configs matching the patterns periodically and listens them,
+// the configs matched at the first search are listened as ListenConfig, the configs matched later
+// are notified with their content as ADDED and the configs no longer matched are notified as DELETED
+func (client *ConfigClient) FuzzyListenConfig(param vo.FuzzyListenConfigParam) (handle vo.ListenerHandle, err error) {
+	if len(param.DataIdPattern) <= 0 && len(param.GroupPattern) <= 0 {
+		err = errors.New("[client.FuzzyListenConfig] DataIdPattern and GroupPattern can not be both empty")
+		return
+	}
+	clientConfig, err := client.GetClientConfig()
+	if err != nil {
+		err = errors.New("[checkConfigInfo.GetClientConfig] failed")
+		return
+	}
+	intervalMs := param.IntervalMs
+	if intervalMs <= 0 {
+		intervalMs = fuzzyListenIntervalMs
+	}
+
+	handle = vo.ListenerHandle(atomic.AddUint64(&client.listenerSeq, 1))
+	ctx, cancel := context.WithCancel(client.ctx)
+	listener := &fuzzyListener{
+		param:   param,
+		cancel:  cancel,
+		matched: make(map[string]vo.ListenerHandle),
+	}
+	client.fuzzyListenerMap.Set(strconv.FormatUint(uint64(handle), 10), listener)
+	client.wg.Add(1)
+	go client.fuzzyListen(ctx, listener, clientConfig.NamespaceId, time.Duration(intervalMs)*time.Millisecond)
+	return
+}
+
+func (client *ConfigClient) fuzzyListen(ctx context.Context, listener *fuzzyListener, tenant string, interval time.Duration) {
+	defer client.wg.Done()
+	initialized := false
+	t := time.NewTimer(0)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			for _, handle := range listener.matched {
+				_ = client.CancelListener(handle)
+			}
+			return
+		case <-t.C:
+		}
+		if err := client.fuzzySearch(ctx, listener, tenant, initialized); err != nil {
+			logger.Errorf("[client.FuzzyListenConfig] search config error, DataIdPattern:%s GroupPattern:%s err:%+v",
+				listener.param.DataIdPattern, listener.param.GroupPattern, err)
+		} else {
+			initialized = true
+		}
+		t.Reset(interval)
+	}
+}
+
+// Search the matching configs, listen the new ones and remove the ones no longer matched
+func (client *ConfigClient) fuzzySearch(ctx context.Context, listener *fuzzyListener, tenant string, initialized bool) error {
+	items, err := client.searchAllConfig(ctx, vo.SearchConfigParam{
+		Search: "blur",
+		DataId: listener.param.DataIdPattern,
+		Group:  listener.param.GroupPattern,
+	})
+	if err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	current := make(map[string]bool, len(items))
+	for _, item := range items {
+		key := util.GetConfigCacheKey(item.DataId, item.Group, tenant)
+		current[key] = true
+		if _, ok := listener.matched[key]; ok {
+			continue
+		}
+		listener.matched[key] = client.addListener(vo.ConfigParam{
+			DataId:        item.DataId,
+			Group:         item.Group,
+			Type:          listener.param.Type,
+			OnChange:      listener.param.OnChange,
+			OnChangeEvent: listener.param.OnChangeEvent,
+		}, tenant, initialized)
+		logger.Infof("[client.FuzzyListenConfig] listen matched config DataId:%s Group:%s", item.DataId, item.Group)
+	}
+	for key, handle := range listener.matched {
+		if current[key] {
+			continue
+		}
+		client.removeDeletedListener(handle)
+		delete(listener.matched, key)
+	}
+	return nil
+}
+
+// Notify the listener that the config is deleted unless it has been told by the long polling, then remove it
+func (client *ConfigClient) removeDeletedListener(handle vo.ListenerHandle) {
+	id := strconv.FormatUint(uint64(handle), 10)
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if key, ok := client.listenerKeyMap.Get(id); ok {
+		if value, ok := client.cacheMap.Get(key.(string)); ok {
+			cData := value.(cacheData)
+			if item, ok := cData.listeners.Get(id); ok && len(item.(*cacheDataListener).lastContent) > 0 {
+				cData.content = ""
+				cData.md5 = ""
+				client.notifyListener(cData, item.(*cacheDataListener))
+			}
+		}
+		logger.Infof("[client.FuzzyListenConfig] remove deleted config %s", key)
+	}
+	_ = client.removeListener(id)
+}
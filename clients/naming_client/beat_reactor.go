@@ -32,6 +32,7 @@ import (
 )
 
 type BeatReactor struct {
+	ctx                 context.Context
 	beatMap             cache.ConcurrentMap
 	serviceProxy        NamingProxy
 	clientBeatInterval  int64
@@ -39,14 +40,13 @@ type BeatReactor struct {
 	beatThreadSemaphore *semaphore.Weighted
 	beatRecordMap       cache.ConcurrentMap
 	mux                 *sync.Mutex
+	wg                  *sync.WaitGroup
 }
 
 const DefaultBeatThreadNum = 20
 
-var ctx = context.Background()
-
-func NewBeatReactor(serviceProxy NamingProxy, clientBeatInterval int64) BeatReactor {
-	br := BeatReactor{}
+func NewBeatReactor(ctx context.Context, serviceProxy NamingProxy, clientBeatInterval int64) BeatReactor {
+	br := BeatReactor{ctx: ctx}
 	if clientBeatInterval <= 0 {
 		clientBeatInterval = 5 * 1000
 	}
@@ -57,6 +57,7 @@ func NewBeatReactor(serviceProxy NamingProxy, clientBeatInterval int64) BeatReac
 	br.beatRecordMap = cache.NewConcurrentMap()
 	br.beatThreadSemaphore = semaphore.NewWeighted(int64(br.beatThreadCount))
 	br.mux = new(sync.Mutex)
+	br.wg = new(sync.WaitGroup)
 	return br
 }
 
@@ -76,6 +77,7 @@ func (br *BeatReactor) AddBeatInfo(serviceName string, beatInfo *model.BeatInfo)
 	}
 	br.beatMap.Set(k, beatInfo)
 	beatInfo.Metadata = util.DeepCopyMap(beatInfo.Metadata)
+	br.wg.Add(1)
 	go br.sendInstanceBeat(k, beatInfo)
 }
 
@@ -93,9 +95,14 @@ func (br *BeatReactor) RemoveBeatInfo(serviceName string, ip string, port uint64
 }
 
 func (br *BeatReactor) sendInstanceBeat(k string, beatInfo *model.BeatInfo) {
+	defer br.wg.Done()
 	for {
-		err := br.beatThreadSemaphore.Acquire(ctx, 1)
+		err := br.beatThreadSemaphore.Acquire(br.ctx, 1)
 		if err != nil {
+			if br.ctx.Err() != nil {
+				logger.Infof("instance[%s] stop heartBeating", k)
+				return
+			}
 			logger.Errorf("sendInstanceBeat failed to acquire semaphore: %v", err)
 			return
 		}
@@ -107,11 +114,13 @@ func (br *BeatReactor) sendInstanceBeat(k string, beatInfo *model.BeatInfo) {
 		}
 
 		//进行心跳通信
-		beatInterval, err := br.serviceProxy.SendBeat(beatInfo)
+		beatInterval, err := br.serviceProxy.SendBeat(br.ctx, beatInfo)
 		if err != nil {
 			logger.Errorf("beat to server return error:%+v", err)
 			br.beatThreadSemaphore.Release(1)
-			time.Sleep(beatInfo.Period)
+			if !br.sleep(beatInfo.Period) {
+				return
+			}
 			continue
 		}
 		if beatInterval > 0 {
@@ -121,6 +130,20 @@ func (br *BeatReactor) sendInstanceBeat(k string, beatInfo *model.BeatInfo) {
 		br.beatRecordMap.Set(k, util.CurrentMillis())
 		br.beatThreadSemaphore.Release(1)
 
-		time.Sleep(beatInfo.Period)
+		if !br.sleep(beatInfo.Period) {
+			return
+		}
+	}
+}
+
+// sleep return false when the beat reactor is closed before d elapses
+func (br *BeatReactor) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-br.ctx.Done():
+		return false
+	case <-timer.C:
+		return true
 	}
 }
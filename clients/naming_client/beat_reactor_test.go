@@ -17,6 +17,7 @@
 package naming_client
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -28,7 +29,7 @@ import (
 )
 
 func TestBeatReactor_AddBeatInfo(t *testing.T) {
-	br := NewBeatReactor(NamingProxy{nacosServer: &nacos_server.NacosServer{}}, 5000)
+	br := NewBeatReactor(context.Background(), NamingProxy{nacosServer: &nacos_server.NacosServer{}}, 5000)
 	serviceName := "Test"
 	groupName := "public"
 	beatInfo := &model.BeatInfo{
@@ -48,7 +49,7 @@ func TestBeatReactor_AddBeatInfo(t *testing.T) {
 }
 
 func TestBeatReactor_RemoveBeatInfo(t *testing.T) {
-	br := NewBeatReactor(NamingProxy{nacosServer: &nacos_server.NacosServer{}}, 5000)
+	br := NewBeatReactor(context.Background(), NamingProxy{nacosServer: &nacos_server.NacosServer{}}, 5000)
 	serviceName := "Test"
 	groupName := "public"
 	beatInfo1 := &model.BeatInfo{
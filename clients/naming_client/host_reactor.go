@@ -17,6 +17,7 @@
 package naming_client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,6 +25,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/clients/cache"
@@ -33,8 +35,10 @@ import (
 )
 
 type HostReactor struct {
+	ctx                  context.Context
+	wg                   *sync.WaitGroup
 	serviceInfoMap       cache.ConcurrentMap
-	cacheDir             string
+	cacheStore           cache.CacheStore
 	updateThreadNum      int
 	serviceProxy         NamingProxy
 	pushReceiver         PushReceiver
@@ -45,30 +49,33 @@ type HostReactor struct {
 
 const Default_Update_Thread_Num = 20
 
-func NewHostReactor(serviceProxy NamingProxy, cacheDir string, updateThreadNum int, notLoadCacheAtStart bool, subCallback SubscribeCallback, updateCacheWhenEmpty bool) HostReactor {
+func NewHostReactor(ctx context.Context, serviceProxy NamingProxy, cacheStore cache.CacheStore, updateThreadNum int, notLoadCacheAtStart bool, subCallback SubscribeCallback, updateCacheWhenEmpty bool) HostReactor {
 	if updateThreadNum <= 0 {
 		updateThreadNum = Default_Update_Thread_Num
 	}
 	hr := HostReactor{
+		ctx:                  ctx,
+		wg:                   new(sync.WaitGroup),
 		serviceProxy:         serviceProxy,
-		cacheDir:             cacheDir,
+		cacheStore:           cacheStore,
 		updateThreadNum:      updateThreadNum,
 		serviceInfoMap:       cache.NewConcurrentMap(),
 		subCallback:          subCallback,
 		updateTimeMap:        cache.NewConcurrentMap(),
 		updateCacheWhenEmpty: updateCacheWhenEmpty,
 	}
-	pr := NewPushReceiver(&hr)
+	pr := NewPushReceiver(ctx, &hr)
 	hr.pushReceiver = *pr
 	if !notLoadCacheAtStart {
 		hr.loadCacheFromDisk()
 	}
+	hr.wg.Add(1)
 	go hr.asyncUpdateService()
 	return hr
 }
 
 func (hr *HostReactor) loadCacheFromDisk() {
-	serviceMap := cache.ReadServicesFromFile(hr.cacheDir)
+	serviceMap := cache.ReadServicesFromStore(hr.cacheStore)
 	if len(serviceMap) == 0 {
 		return
 	}
@@ -101,17 +108,20 @@ func (hr *HostReactor) ProcessServiceJson(result string) {
 		} else {
 			logger.Info("service key:%s was updated to:%s", cacheKey, util.ToJsonString(service))
 		}
-		cache.WriteServicesToFile(*service, hr.cacheDir)
+		cache.WriteServiceToStore(hr.cacheStore, *service)
 		hr.subCallback.ServiceChanged(service)
 	}
 }
 
-func (hr *HostReactor) GetServiceInfo(serviceName string, clusters string) (model.Service, error) {
+func (hr *HostReactor) GetServiceInfo(ctx context.Context, serviceName string, clusters string) (model.Service, error) {
 	key := util.GetServiceCacheKey(serviceName, clusters)
 	cacheService, ok := hr.serviceInfoMap.Get(key)
 	if !ok {
-		hr.updateServiceNow(serviceName, clusters)
+		hr.updateServiceNow(ctx, serviceName, clusters)
 		if cacheService, ok = hr.serviceInfoMap.Get(key); !ok {
+			if ctx.Err() != nil {
+				return model.Service{}, ctx.Err()
+			}
 			return model.Service{}, errors.New("get service info failed")
 		}
 	}
@@ -119,9 +129,9 @@ func (hr *HostReactor) GetServiceInfo(serviceName string, clusters string) (mode
 	return cacheService.(model.Service), nil
 }
 
-func (hr *HostReactor) GetAllServiceInfo(nameSpace, groupName string, pageNo, pageSize uint32) (model.ServiceList, error) {
+func (hr *HostReactor) GetAllServiceInfo(ctx context.Context, nameSpace, groupName string, pageNo, pageSize uint32) (model.ServiceList, error) {
 	data := model.ServiceList{}
-	result, err := hr.serviceProxy.GetAllServiceInfoList(nameSpace, groupName, pageNo, pageSize)
+	result, err := hr.serviceProxy.GetAllServiceInfoList(ctx, nameSpace, groupName, pageNo, pageSize)
 	if err != nil {
 		logger.Errorf("GetAllServiceInfoList return error!nameSpace:%s groupName:%s pageNo:%d, pageSize:%d err:%+v",
 			nameSpace, groupName, pageNo, pageSize, err)
@@ -142,8 +152,8 @@ func (hr *HostReactor) GetAllServiceInfo(nameSpace, groupName string, pageNo, pa
 	return data, nil
 }
 
-func (hr *HostReactor) updateServiceNow(serviceName, clusters string) {
-	result, err := hr.serviceProxy.QueryList(serviceName, clusters, hr.pushReceiver.port, false)
+func (hr *HostReactor) updateServiceNow(ctx context.Context, serviceName, clusters string) {
+	result, err := hr.serviceProxy.QueryList(ctx, serviceName, clusters, hr.pushReceiver.port, false)
 
 	if err != nil {
 		logger.Errorf("QueryList return error!serviceName:%s cluster:%s err:%+v", serviceName, clusters, err)
@@ -157,7 +167,10 @@ func (hr *HostReactor) updateServiceNow(serviceName, clusters string) {
 }
 
 func (hr *HostReactor) asyncUpdateService() {
+	defer hr.wg.Done()
 	sema := util.NewSemaphore(hr.updateThreadNum)
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
 	for {
 		for _, v := range hr.serviceInfoMap.Items() {
 			service := v.(model.Service)
@@ -167,13 +180,19 @@ func (hr *HostReactor) asyncUpdateService() {
 			}
 			if uint64(util.CurrentMillis())-lastRefTime.(uint64) > service.CacheMillis {
 				sema.Acquire()
+				hr.wg.Add(1)
 				go func() {
-					hr.updateServiceNow(service.Name, service.Clusters)
+					defer hr.wg.Done()
+					hr.updateServiceNow(hr.ctx, service.Name, service.Clusters)
 					sema.Release()
 				}()
 			}
 		}
-		time.Sleep(1 * time.Second)
+		select {
+		case <-hr.ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
 }
 
@@ -227,9 +246,9 @@ func sortInstance(instances []model.Instance) {
 	sort.Sort(instanceSorter(instances))
 }
 
-func (hr *HostReactor) GetCatalogServices(nameSpace string, pageNo, pageSize uint32) model.CatalogServiceList {
+func (hr *HostReactor) GetCatalogServices(ctx context.Context, nameSpace string, pageNo, pageSize uint32) model.CatalogServiceList {
 	data := model.CatalogServiceList{}
-	result, err := hr.serviceProxy.GetCatalogServiceList(nameSpace, pageNo, pageSize)
+	result, err := hr.serviceProxy.GetCatalogServiceList(ctx, nameSpace, pageNo, pageSize)
 	if err != nil {
 		logger.Errorf("GetCatalogServices return error! namespace:%s err:%+v", nameSpace, err)
 		return data
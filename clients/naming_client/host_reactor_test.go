@@ -17,6 +17,7 @@
 package naming_client
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"testing"
@@ -55,7 +56,7 @@ func TestHostReactor_GetServiceInfo(t *testing.T) {
 	}
 	param.ServiceName = util.GetGroupName(param.ServiceName, param.GroupName)
 	_, _ = client.RegisterInstance(param)
-	_, err := client.hostReactor.GetServiceInfo(param.ServiceName, param.ClusterName)
+	_, err := client.hostReactor.GetServiceInfo(context.Background(), param.ServiceName, param.ClusterName)
 	assert.Nil(t, err)
 }
 
@@ -76,7 +77,7 @@ func TestHostReactor_GetServiceInfoErr(t *testing.T) {
 		Ephemeral:   true,
 	}
 	_, _ = client.RegisterInstance(param)
-	_, err := client.hostReactor.GetServiceInfo(param.ServiceName, param.ClusterName)
+	_, err := client.hostReactor.GetServiceInfo(context.Background(), param.ServiceName, param.ClusterName)
 	assert.NotNil(t, err)
 }
 
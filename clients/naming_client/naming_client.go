@@ -17,10 +17,9 @@
 package naming_client
 
 import (
-	"math"
+	"context"
 	"math/rand"
 	"os"
-	"sort"
 	"strings"
 	"time"
 
@@ -28,8 +27,10 @@ import (
 
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/clients/cache"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/clients/nacos_client"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/balancer"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/constant"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/selector"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
@@ -37,28 +38,52 @@ import (
 
 type NamingClient struct {
 	nacos_client.INacosClient
+	ctx          context.Context
+	cancel       context.CancelFunc
 	hostReactor  HostReactor
 	serviceProxy NamingProxy
 	subCallback  SubscribeCallback
 	beatReactor  BeatReactor
 	indexMap     cache.ConcurrentMap
 	NamespaceId  string
+	warmUp       *util.WarmUp
+	balancer     balancer.Balancer
+	protector    *protector
+	outliers     *outlierDetector
+
+	// the locality preference of SelectOneHealthyInstance, see preferLocalZone
+	zone               string
+	zoneMetadataKey    string
+	zoneSpillThreshold float64
 }
 
-type Chooser struct {
-	data   []model.Instance
-	totals []int
-	max    int
-}
+const warmUpRetryDelay = 1 * time.Second
 
 func NewNamingClient(nc nacos_client.INacosClient) (NamingClient, error) {
 	rand.Seed(time.Now().UnixNano())
 	naming := NamingClient{INacosClient: nc}
+	naming.ctx, naming.cancel = context.WithCancel(context.Background())
 	clientConfig, err := nc.GetClientConfig()
 	if err != nil {
 		return naming, err
 	}
+	// validate before the reactors are started, so the failed client leaves nothing running
+	for _, service := range clientConfig.WarmUpServices {
+		if len(service.ServiceName) == 0 {
+			return naming, errors.New("serviceName of the warm-up service cannot be empty!")
+		}
+	}
 	naming.NamespaceId = clientConfig.NamespaceId
+	naming.balancer = clientConfig.Balancer
+	if naming.balancer == nil {
+		naming.balancer = balancer.NewWeightedRandom()
+	}
+	naming.zone = clientConfig.Zone
+	naming.zoneMetadataKey = clientConfig.ZoneMetadataKey
+	if naming.zoneMetadataKey == "" {
+		naming.zoneMetadataKey = constant.DEFAULT_ZONE_METADATA_KEY
+	}
+	naming.zoneSpillThreshold = clientConfig.ZoneSpillThreshold
 	serverConfig, err := nc.GetServerConfig()
 	if err != nil {
 		return naming, err
@@ -82,19 +107,80 @@ func NewNamingClient(nc nacos_client.INacosClient) (NamingClient, error) {
 	}
 	logger.GetLogger().Infof("logDir:<%s>   cacheDir:<%s>", clientConfig.LogDir, clientConfig.CacheDir)
 	naming.subCallback = NewSubscribeCallback()
-	naming.serviceProxy, err = NewNamingProxy(clientConfig, serverConfig, httpAgent)
+	naming.serviceProxy, err = NewNamingProxy(naming.ctx, clientConfig, serverConfig, httpAgent)
+	if err != nil {
+		naming.cancel()
+		return naming, err
+	}
+	namingCacheStore, err := cache.NewCacheStore(clientConfig.CacheStoreType, clientConfig.CacheDir+string(os.PathSeparator)+"naming")
 	if err != nil {
+		naming.cancel()
 		return naming, err
 	}
-	naming.hostReactor = NewHostReactor(naming.serviceProxy, clientConfig.CacheDir+string(os.PathSeparator)+"naming",
+	if clientConfig.CacheMaxAgeMs > 0 {
+		namingCacheStore.Prune(time.Duration(clientConfig.CacheMaxAgeMs) * time.Millisecond)
+	}
+	if clientConfig.UseProtectThreshold {
+		naming.protector = newProtector(naming.ctx, naming.serviceProxy, clientConfig.OnProtectChange)
+	}
+	naming.outliers = newOutlierDetector(clientConfig.OutlierDetection)
+	naming.hostReactor = NewHostReactor(naming.ctx, naming.serviceProxy, namingCacheStore,
 		clientConfig.UpdateThreadNum, clientConfig.NotLoadCacheAtStart, naming.subCallback, clientConfig.UpdateCacheWhenEmpty)
-	naming.beatReactor = NewBeatReactor(naming.serviceProxy, clientConfig.BeatInterval)
+	naming.beatReactor = NewBeatReactor(naming.ctx, naming.serviceProxy, clientConfig.BeatInterval)
 	naming.indexMap = cache.NewConcurrentMap()
+	naming.warmUpServices(clientConfig)
 	return naming, nil
 }
 
+// Load the warm-up services in parallel, and wait for them at most WarmUpTimeoutMs,
+// the services not loaded in time keep loading in background
+func (sc *NamingClient) warmUpServices(clientConfig constant.ClientConfig) {
+	services := clientConfig.WarmUpServices
+	sc.warmUp = util.NewWarmUp(sc.ctx, sc.hostReactor.wg, len(services), warmUpRetryDelay, func(ctx context.Context, index int) error {
+		service := services[index]
+		if len(service.GroupName) == 0 {
+			service.GroupName = constant.DEFAULT_GROUP
+		}
+		_, err := sc.hostReactor.GetServiceInfo(ctx, util.GetGroupName(service.ServiceName, service.GroupName), strings.Join(service.Clusters, ","))
+		if err != nil {
+			logger.Warnf("load warm-up service failed, serviceName:%s groupName:%s err:%+v", service.ServiceName, service.GroupName, err)
+		}
+		return err
+	})
+	if len(services) == 0 {
+		return
+	}
+	timeoutMs := clientConfig.WarmUpTimeoutMs
+	if timeoutMs == 0 {
+		timeoutMs = clientConfig.TimeoutMs
+	}
+	ctx, cancel := context.WithTimeout(sc.ctx, time.Duration(timeoutMs)*time.Millisecond)
+	defer cancel()
+	if err := sc.warmUp.WaitReady(ctx); err != nil {
+		logger.Warnf("the warm-up services are not loaded in %d ms, keep loading them in background", timeoutMs)
+	}
+}
+
+// Ready returns true if all the ClientConfig.WarmUpServices are loaded from server or the cache
+func (sc *NamingClient) Ready() bool {
+	return sc.warmUp != nil && sc.warmUp.Ready()
+}
+
+// WaitReady waits until all the ClientConfig.WarmUpServices are loaded, ctx.Err() is returned if ctx is done before that
+func (sc *NamingClient) WaitReady(ctx context.Context) error {
+	if sc.warmUp == nil {
+		return errors.New("the client is not created successfully")
+	}
+	return sc.warmUp.WaitReady(ctx)
+}
+
 // RegisterInstance register instance
 func (sc *NamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool, error) {
+	return sc.RegisterInstanceWithContext(context.Background(), param)
+}
+
+// RegisterInstanceWithContext is the same as RegisterInstance, the request is aborted when ctx is done
+func (sc *NamingClient) RegisterInstanceWithContext(ctx context.Context, param vo.RegisterInstanceParam) (bool, error) {
 	if param.ServiceName == "" {
 		return false, errors.New("serviceName cannot be empty!")
 	}
@@ -124,7 +210,7 @@ func (sc *NamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool,
 		Period:      util.GetDurationWithDefault(param.Metadata, constant.HEART_BEAT_INTERVAL, time.Second*5),
 		State:       model.StateRunning,
 	}
-	_, err := sc.serviceProxy.RegisterInstance(util.GetGroupName(param.ServiceName, param.GroupName), param.GroupName, instance)
+	_, err := sc.serviceProxy.RegisterInstance(ctx, util.GetGroupName(param.ServiceName, param.GroupName), param.GroupName, instance)
 	if err != nil {
 		return false, err
 	}
@@ -137,12 +223,17 @@ func (sc *NamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool,
 
 // DeregisterInstance deregister instance
 func (sc *NamingClient) DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error) {
+	return sc.DeregisterInstanceWithContext(context.Background(), param)
+}
+
+// DeregisterInstanceWithContext is the same as DeregisterInstance, the request is aborted when ctx is done
+func (sc *NamingClient) DeregisterInstanceWithContext(ctx context.Context, param vo.DeregisterInstanceParam) (bool, error) {
 	if len(param.GroupName) == 0 {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
 	sc.beatReactor.RemoveBeatInfo(util.GetGroupName(param.ServiceName, param.GroupName), param.Ip, param.Port)
 
-	_, err := sc.serviceProxy.DeregisterInstance(util.GetGroupName(param.ServiceName, param.GroupName), param.Ip, param.Port, param.Cluster, param.Ephemeral)
+	_, err := sc.serviceProxy.DeregisterInstance(ctx, util.GetGroupName(param.ServiceName, param.GroupName), param.Ip, param.Port, param.Cluster, param.Ephemeral)
 	if err != nil {
 		return false, err
 	}
@@ -151,6 +242,11 @@ func (sc *NamingClient) DeregisterInstance(param vo.DeregisterInstanceParam) (bo
 
 // UpdateInstance update information for exist instance.
 func (sc *NamingClient) UpdateInstance(param vo.UpdateInstanceParam) (bool, error) {
+	return sc.UpdateInstanceWithContext(context.Background(), param)
+}
+
+// UpdateInstanceWithContext is the same as UpdateInstance, the request is aborted when ctx is done
+func (sc *NamingClient) UpdateInstanceWithContext(ctx context.Context, param vo.UpdateInstanceParam) (bool, error) {
 	if len(param.GroupName) == 0 {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
@@ -173,7 +269,7 @@ func (sc *NamingClient) UpdateInstance(param vo.UpdateInstanceParam) (bool, erro
 	}
 
 	// Do update instance
-	_, err := sc.serviceProxy.UpdateInstance(
+	_, err := sc.serviceProxy.UpdateInstance(ctx,
 		util.GetGroupName(param.ServiceName, param.GroupName), param.Ip, param.Port, param.ClusterName, param.Ephemeral,
 		param.Weight, param.Enable, param.Metadata)
 
@@ -185,15 +281,25 @@ func (sc *NamingClient) UpdateInstance(param vo.UpdateInstanceParam) (bool, erro
 
 // GetService get service info
 func (sc *NamingClient) GetService(param vo.GetServiceParam) (model.Service, error) {
+	return sc.GetServiceWithContext(context.Background(), param)
+}
+
+// GetServiceWithContext is the same as GetService, the request is aborted when ctx is done
+func (sc *NamingClient) GetServiceWithContext(ctx context.Context, param vo.GetServiceParam) (model.Service, error) {
 	if len(param.GroupName) == 0 {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
-	service, err := sc.hostReactor.GetServiceInfo(util.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	service, err := sc.hostReactor.GetServiceInfo(ctx, util.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
 	return service, err
 }
 
 // GetAllServicesInfo get all services info
 func (sc *NamingClient) GetAllServicesInfo(param vo.GetAllServiceInfoParam) (model.ServiceList, error) {
+	return sc.GetAllServicesInfoWithContext(context.Background(), param)
+}
+
+// GetAllServicesInfoWithContext is the same as GetAllServicesInfo, the request is aborted when ctx is done
+func (sc *NamingClient) GetAllServicesInfoWithContext(ctx context.Context, param vo.GetAllServiceInfoParam) (model.ServiceList, error) {
 	if len(param.GroupName) == 0 {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
@@ -210,15 +316,20 @@ func (sc *NamingClient) GetAllServicesInfo(param vo.GetAllServiceInfoParam) (mod
 	if param.PageSize == 0 {
 		param.PageSize = 10
 	}
-	return sc.hostReactor.GetAllServiceInfo(param.NameSpace, param.GroupName, param.PageNo, param.PageSize)
+	return sc.hostReactor.GetAllServiceInfo(ctx, param.NameSpace, param.GroupName, param.PageNo, param.PageSize)
 }
 
 // SelectAllInstances select all instances
 func (sc *NamingClient) SelectAllInstances(param vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	return sc.SelectAllInstancesWithContext(context.Background(), param)
+}
+
+// SelectAllInstancesWithContext is the same as SelectAllInstances, the request is aborted when ctx is done
+func (sc *NamingClient) SelectAllInstancesWithContext(ctx context.Context, param vo.SelectAllInstancesParam) ([]model.Instance, error) {
 	if len(param.GroupName) == 0 {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
-	service, err := sc.hostReactor.GetServiceInfo(util.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	service, err := sc.hostReactor.GetServiceInfo(ctx, util.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
 	if err != nil || service.Hosts == nil || len(service.Hosts) == 0 {
 		return []model.Instance{}, err
 	}
@@ -227,14 +338,32 @@ func (sc *NamingClient) SelectAllInstances(param vo.SelectAllInstancesParam) ([]
 
 // SelectInstances select instances
 func (sc *NamingClient) SelectInstances(param vo.SelectInstancesParam) ([]model.Instance, error) {
+	return sc.SelectInstancesWithContext(context.Background(), param)
+}
+
+// SelectInstancesWithContext is the same as SelectInstances, the request is aborted when ctx is done
+func (sc *NamingClient) SelectInstancesWithContext(ctx context.Context, param vo.SelectInstancesParam) ([]model.Instance, error) {
 	if len(param.GroupName) == 0 {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
-	service, err := sc.hostReactor.GetServiceInfo(util.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	sel, err := selector.Parse(param.Selector)
+	if err != nil {
+		return nil, err
+	}
+	service, err := sc.hostReactor.GetServiceInfo(ctx, util.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
 	if err != nil {
 		return nil, err
 	}
-	return sc.selectInstances(service, param.HealthyOnly)
+	var instances []model.Instance
+	if param.HealthyOnly && sc.protector.check(service) {
+		instances = protectedInstances(service.Hosts)
+	} else if instances, err = sc.selectInstances(service, param.HealthyOnly); err != nil {
+		return instances, err
+	}
+	if param.HealthyOnly {
+		instances = sc.outliers.filter(service.Hosts, instances)
+	}
+	return selectByMetadata(instances, sel, param.SelectorFallback, anyInstance), nil
 }
 
 func (sc *NamingClient) selectInstances(service model.Service, healthy bool) ([]model.Instance, error) {
@@ -244,94 +373,177 @@ func (sc *NamingClient) selectInstances(service model.Service, healthy bool) ([]
 	hosts := service.Hosts
 	var result []model.Instance
 	for _, host := range hosts {
-		if host.Healthy == healthy && host.Enable && host.Weight > 0 {
+		if usableInstance(host, healthy) {
 			result = append(result, host)
 		}
 	}
 	return result, nil
 }
 
+// protectedInstances returns the enabled hosts with a positive weight regardless of their health, which are selected
+// when the service is in the protection mode of its protect threshold
+func protectedInstances(hosts []model.Instance) []model.Instance {
+	var result []model.Instance
+	for _, host := range hosts {
+		if host.Enable && host.Weight > 0 {
+			result = append(result, host)
+		}
+	}
+	return result
+}
+
+func anyInstance(model.Instance) bool {
+	return true
+}
+
+func usableInstance(host model.Instance, healthy bool) bool {
+	return host.Healthy == healthy && host.Enable && host.Weight > 0
+}
+
+// selectByMetadata keeps the hosts whose metadata match sel, if fallback is set and none of the usable hosts
+// matches sel, the hosts are returned as they are
+func selectByMetadata(hosts []model.Instance, sel *selector.Selector, fallback bool, usable func(host model.Instance) bool) []model.Instance {
+	if sel.Empty() {
+		return hosts
+	}
+	matched := sel.Filter(hosts)
+	if !fallback {
+		return matched
+	}
+	for _, host := range matched {
+		if usable(host) {
+			return matched
+		}
+	}
+	logger.Debugf("no instance matches the selector %s, fallback to all the instances", sel)
+	return hosts
+}
+
 // SelectOneHealthyInstance select one healthy instance
 func (sc *NamingClient) SelectOneHealthyInstance(param vo.SelectOneHealthInstanceParam) (*model.Instance, error) {
+	return sc.SelectOneHealthyInstanceWithContext(context.Background(), param)
+}
+
+// SelectOneHealthyInstanceWithContext is the same as SelectOneHealthyInstance, the request is aborted when ctx is done
+func (sc *NamingClient) SelectOneHealthyInstanceWithContext(ctx context.Context, param vo.SelectOneHealthInstanceParam) (*model.Instance, error) {
 	if len(param.GroupName) == 0 {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
-	service, err := sc.hostReactor.GetServiceInfo(util.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	sel, err := selector.Parse(param.Selector)
 	if err != nil {
 		return nil, err
 	}
-	return sc.selectOneHealthyInstances(service)
+	service, err := sc.hostReactor.GetServiceInfo(ctx, util.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","))
+	if err != nil {
+		return nil, err
+	}
+	protected := sc.protector.check(service)
+	return sc.selectOneHealthyInstances(service, protected, sel, param.SelectorFallback, param.Balancer, param.HashKey)
 }
 
-func (sc *NamingClient) selectOneHealthyInstances(service model.Service) (*model.Instance, error) {
+// selectOneHealthyInstances picks one of the healthy hosts, or one of all the hosts when protected. The hosts go
+// through the outlier detection and then the metadata selector, in the same order as SelectInstances
+func (sc *NamingClient) selectOneHealthyInstances(service model.Service, protected bool, sel *selector.Selector, fallback bool,
+	b balancer.Balancer, hashKey string) (*model.Instance, error) {
 	if service.Hosts == nil || len(service.Hosts) == 0 {
 		return nil, errors.New("instance list is empty!")
 	}
 	hosts := service.Hosts
 	var result []model.Instance
-	mw := 0
 	for _, host := range hosts {
-		if host.Healthy && host.Enable && host.Weight > 0 {
-			cw := int(math.Ceil(host.Weight))
-			if cw > mw {
-				mw = cw
-			}
+		if usableInstance(host, true) || (protected && usableInstance(host, false)) {
 			result = append(result, host)
 		}
 	}
+	result = sc.outliers.filter(hosts, result)
+	result = selectByMetadata(result, sel, fallback, anyInstance)
 	if len(result) == 0 {
 		return nil, errors.New("healthy instance list is empty!")
 	}
-
-	instance := newChooser(result).pick()
+	result = sc.preferLocalZone(hosts, result)
+	if b == nil {
+		b = sc.balancer
+	}
+	instance := b.Pick(balancer.Request{
+		Service:   util.GetServiceCacheKey(service.Name, service.Clusters),
+		Instances: result,
+		HashKey:   hashKey,
+	})
 	return &instance, nil
 }
 
-type instances []model.Instance
-
-func (a instances) Len() int {
-	return len(a)
-}
-
-func (a instances) Swap(i, j int) {
-	a[i], a[j] = a[j], a[i]
+// preferLocalZone narrows the healthy hosts down to the ones in the zone of the client, unless the healthy weight
+// ratio of the zone, the weight of its healthy hosts over the weight of all its enabled hosts, is below the spill
+// threshold, then the hosts in the other zones share the traffic
+func (sc *NamingClient) preferLocalZone(hosts []model.Instance, healthy []model.Instance) []model.Instance {
+	if sc.zone == "" {
+		return healthy
+	}
+	var local []model.Instance
+	healthyWeight := 0.0
+	for _, host := range healthy {
+		if host.Metadata[sc.zoneMetadataKey] == sc.zone {
+			local = append(local, host)
+			healthyWeight += host.Weight
+		}
+	}
+	if len(local) == 0 || len(local) == len(healthy) {
+		return healthy
+	}
+	totalWeight := 0.0
+	for _, host := range hosts {
+		if host.Enable && host.Weight > 0 && host.Metadata[sc.zoneMetadataKey] == sc.zone {
+			totalWeight += host.Weight
+		}
+	}
+	if healthyWeight/totalWeight < sc.zoneSpillThreshold {
+		logger.Debugf("the healthy weight ratio of zone %s is %.2f, spill over to the other zones", sc.zone, healthyWeight/totalWeight)
+		return healthy
+	}
+	return local
 }
 
-func (a instances) Less(i, j int) bool {
-	return a[i].Weight < a[j].Weight
+// GetProtectStates returns the protect threshold states of the selected services, see ClientConfig.UseProtectThreshold
+func (sc *NamingClient) GetProtectStates() []model.ProtectState {
+	return sc.protector.protectStates()
 }
 
-// NewChooser initializes a new Chooser for picking from the provided Choices.
-func newChooser(instances instances) Chooser {
-	sort.Sort(instances)
-	totals := make([]int, len(instances))
-	runningTotal := 0
-	for i, c := range instances {
-		runningTotal += int(c.Weight)
-		totals[i] = runningTotal
+// ReportResult reports the result of a call to an instance selected by SelectOneHealthyInstance or SelectInstances,
+// the instances with consecutive failures or a high error rate are ejected from the selection for a while,
+// see ClientConfig.OutlierDetection
+func (sc *NamingClient) ReportResult(instance model.Instance, err error, latency time.Duration) {
+	if sc.outliers != nil {
+		sc.outliers.report(instance, err, latency)
 	}
-	return Chooser{data: instances, totals: totals, max: runningTotal}
 }
 
-func (chs Chooser) pick() model.Instance {
-	r := rand.Intn(chs.max) + 1
-	i := sort.SearchInts(chs.totals, r)
-	return chs.data[i]
+// GetOutlierStats returns the outlier detection states of the instances reported by ReportResult
+func (sc *NamingClient) GetOutlierStats() []model.OutlierStats {
+	return sc.outliers.outlierStats()
 }
 
 // Subscribe subscribe service
 func (sc *NamingClient) Subscribe(param *vo.SubscribeParam) error {
+	return sc.SubscribeWithContext(context.Background(), param)
+}
+
+// SubscribeWithContext is the same as Subscribe, the request is aborted when ctx is done
+func (sc *NamingClient) SubscribeWithContext(ctx context.Context, param *vo.SubscribeParam) error {
 	if len(param.GroupName) == 0 {
 		param.GroupName = constant.DEFAULT_GROUP
 	}
+	sel, err := selector.Parse(param.Selector)
+	if err != nil {
+		return err
+	}
 	serviceParam := vo.GetServiceParam{
 		ServiceName: param.ServiceName,
 		GroupName:   param.GroupName,
 		Clusters:    param.Clusters,
 	}
 
-	sc.subCallback.AddCallbackFuncs(util.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","), &param.SubscribeCallback)
-	svc, err := sc.GetService(serviceParam)
+	sc.subCallback.AddSelectorCallbackFuncs(util.GetGroupName(param.ServiceName, param.GroupName), strings.Join(param.Clusters, ","), &param.SubscribeCallback, sel, param.SelectorFallback)
+	svc, err := sc.GetServiceWithContext(ctx, serviceParam)
 	if err != nil {
 		return err
 	}
@@ -349,8 +561,35 @@ func (sc *NamingClient) Unsubscribe(param *vo.SubscribeParam) error {
 
 // GetCatalogServices get all services from the Nacos catalog
 func (sc *NamingClient) GetCatalogServices(namesSpace string) (model.CatalogServiceList, error) {
+	return sc.GetCatalogServicesWithContext(context.Background(), namesSpace)
+}
+
+// GetCatalogServicesWithContext is the same as GetCatalogServices, the request is aborted when ctx is done
+func (sc *NamingClient) GetCatalogServicesWithContext(ctx context.Context, namesSpace string) (model.CatalogServiceList, error) {
 	if len(namesSpace) == 0 {
 		namesSpace = constant.DEFAULT_NAMESPACE_ID
 	}
-	return sc.hostReactor.GetCatalogServices(namesSpace, 1, 10000), nil
+	return sc.hostReactor.GetCatalogServices(ctx, namesSpace, 1, 10000), ctx.Err()
+}
+
+// CloseClient stops all the goroutines of the client and waits for the in-flight subscribe callbacks,
+// the ephemeral instances registered by this client are deregistered first when DeregisterAtClose is set
+func (sc *NamingClient) CloseClient() {
+	clientConfig, _ := sc.GetClientConfig()
+	if clientConfig.DeregisterAtClose {
+		for _, v := range sc.beatReactor.beatMap.Items() {
+			beatInfo := v.(*model.BeatInfo)
+			sc.beatReactor.RemoveBeatInfo(beatInfo.ServiceName, beatInfo.Ip, beatInfo.Port)
+			_, err := sc.serviceProxy.DeregisterInstance(context.Background(), beatInfo.ServiceName, beatInfo.Ip, beatInfo.Port, beatInfo.Cluster, true)
+			if err != nil {
+				logger.Errorf("deregister instance %s@%s:%d at close failed,err:%+v", beatInfo.ServiceName, beatInfo.Ip, beatInfo.Port, err)
+			}
+		}
+	}
+	sc.cancel()
+	sc.hostReactor.wg.Wait()
+	sc.beatReactor.wg.Wait()
+	sc.serviceProxy.nacosServer.Wait()
+	sc.protector.wait()
+	sc.hostReactor.cacheStore.Close()
 }
@@ -16,6 +16,9 @@
 package naming_client
 
 import (
+	"context"
+	"time"
+
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
 )
@@ -38,6 +41,9 @@ type INamingClient interface {
 	//Ephemeral optional
 	RegisterInstance(param vo.RegisterInstanceParam) (bool, error)
 
+	//RegisterInstanceWithContext is the same as RegisterInstance, the request is aborted when ctx is done
+	RegisterInstanceWithContext(ctx context.Context, param vo.RegisterInstanceParam) (bool, error)
+
 	//DeregisterInstance use to deregister instance
 	//Ip required
 	//Port required
@@ -48,6 +54,9 @@ type INamingClient interface {
 	//Ephemeral optional
 	DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error)
 
+	//DeregisterInstanceWithContext is the same as DeregisterInstance, the request is aborted when ctx is done
+	DeregisterInstanceWithContext(ctx context.Context, param vo.DeregisterInstanceParam) (bool, error)
+
 	// UpdateInstance use to modify instance
 	// Ip required
 	// Port required
@@ -61,18 +70,27 @@ type INamingClient interface {
 	// Metadata  optional
 	UpdateInstance(param vo.UpdateInstanceParam) (bool, error)
 
+	//UpdateInstanceWithContext is the same as UpdateInstance, the request is aborted when ctx is done
+	UpdateInstanceWithContext(ctx context.Context, param vo.UpdateInstanceParam) (bool, error)
+
 	//GetService use to get service
 	//ServiceName require
 	//Clusters optional,default:DEFAULT
 	//GroupName optional,default:DEFAULT_GROUP
 	GetService(param vo.GetServiceParam) (model.Service, error)
 
+	//GetServiceWithContext is the same as GetService, the request is aborted when ctx is done
+	GetServiceWithContext(ctx context.Context, param vo.GetServiceParam) (model.Service, error)
+
 	//SelectAllInstance return all instances,include healthy=false,enable=false,weight<=0
 	//ServiceName require
 	//Clusters optional,default:DEFAULT
 	//GroupName optional,default:DEFAULT_GROUP
 	SelectAllInstances(param vo.SelectAllInstancesParam) ([]model.Instance, error)
 
+	//SelectAllInstancesWithContext is the same as SelectAllInstances, the request is aborted when ctx is done
+	SelectAllInstancesWithContext(ctx context.Context, param vo.SelectAllInstancesParam) ([]model.Instance, error)
+
 	//SelectInstances only return the instances of healthy=${HealthyOnly},enable=true and weight>0
 	//ServiceName require
 	//Clusters optional,default:DEFAULT
@@ -80,6 +98,9 @@ type INamingClient interface {
 	//HealthyOnly optional
 	SelectInstances(param vo.SelectInstancesParam) ([]model.Instance, error)
 
+	//SelectInstancesWithContext is the same as SelectInstances, the request is aborted when ctx is done
+	SelectInstancesWithContext(ctx context.Context, param vo.SelectInstancesParam) ([]model.Instance, error)
+
 	//SelectInstances return one instance by WRR strategy for load balance
 	//And the instance should be health=true,enable=true and weight>0
 	//ServiceName require
@@ -87,6 +108,20 @@ type INamingClient interface {
 	//GroupName optional,default:DEFAULT_GROUP
 	SelectOneHealthyInstance(param vo.SelectOneHealthInstanceParam) (*model.Instance, error)
 
+	//SelectOneHealthyInstanceWithContext is the same as SelectOneHealthyInstance, the request is aborted when ctx is done
+	SelectOneHealthyInstanceWithContext(ctx context.Context, param vo.SelectOneHealthInstanceParam) (*model.Instance, error)
+
+	//GetProtectStates returns the protect threshold states of the services selected by SelectInstances and SelectOneHealthyInstance
+	//it's empty unless ClientConfig.UseProtectThreshold is true, ClientConfig.OnProtectChange is called when the state changes
+	GetProtectStates() []model.ProtectState
+
+	//ReportResult reports the result of a call to an instance selected by SelectOneHealthyInstance or SelectInstances
+	//the instances with consecutive failures or a high error rate are ejected from the selection for a while, see ClientConfig.OutlierDetection
+	ReportResult(instance model.Instance, err error, latency time.Duration)
+
+	//GetOutlierStats returns the outlier detection states of the instances reported by ReportResult
+	GetOutlierStats() []model.OutlierStats
+
 	//Subscribe use to subscribe service change event
 	//ServiceName require
 	//Clusters optional,default:DEFAULT
@@ -94,6 +129,9 @@ type INamingClient interface {
 	//SubscribeCallback require
 	Subscribe(param *vo.SubscribeParam) error
 
+	//SubscribeWithContext is the same as Subscribe, the request is aborted when ctx is done
+	SubscribeWithContext(ctx context.Context, param *vo.SubscribeParam) error
+
 	//Unsubscribe use to unsubscribe service change event
 	//ServiceName require
 	//Clusters optional,default:DEFAULT
@@ -104,6 +142,23 @@ type INamingClient interface {
 	//GetAllServicesInfo use to get all service info by page
 	GetAllServicesInfo(param vo.GetAllServiceInfoParam) (model.ServiceList, error)
 
+	//GetAllServicesInfoWithContext is the same as GetAllServicesInfo, the request is aborted when ctx is done
+	GetAllServicesInfoWithContext(ctx context.Context, param vo.GetAllServiceInfoParam) (model.ServiceList, error)
+
 	//GetCatalogServices get all services from the Nacos catalog
 	GetCatalogServices(namesSpace string) (model.CatalogServiceList, error)
+
+	//GetCatalogServicesWithContext is the same as GetCatalogServices, the request is aborted when ctx is done
+	GetCatalogServicesWithContext(ctx context.Context, namesSpace string) (model.CatalogServiceList, error)
+
+	//Ready returns true if all the ClientConfig.WarmUpServices are loaded from server or the cache
+	Ready() bool
+
+	//WaitReady waits until all the ClientConfig.WarmUpServices are loaded, ctx.Err() is returned if ctx is done before that
+	//it can be used to gate the traffic by the readiness probe
+	WaitReady(ctx context.Context) error
+
+	//CloseClient use to stop all the goroutines and close the udp push receiver of the client
+	//the ephemeral instances are deregistered first when ClientConfig.DeregisterAtClose is true
+	CloseClient()
 }
@@ -17,20 +17,29 @@
 package naming_client
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/clients/nacos_client"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/balancer"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/constant"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/http_agent"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/mock"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
 )
 
+// the mock must be regenerated when INamingClient changes
+var _ INamingClient = (*mock.MockINamingClient)(nil)
+
 var clientConfigTest = *constant.NewClientConfig(
 	constant.WithTimeoutMs(10*1000),
 	constant.WithBeatInterval(5*1000),
@@ -45,7 +54,7 @@ func Test_RegisterServiceInstance_withoutGroupName(t *testing.T) {
 		ctrl.Finish()
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("POST"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("POST"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(10*1000)),
@@ -86,7 +95,7 @@ func Test_RegisterServiceInstance_withGroupName(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("POST"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("POST"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(10*1000)),
@@ -129,7 +138,7 @@ func Test_RegisterServiceInstance_withCluster(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("POST"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("POST"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(10*1000)),
@@ -173,7 +182,7 @@ func Test_RegisterServiceInstance_401(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("POST"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("POST"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(10*1000)),
@@ -209,6 +218,33 @@ func Test_RegisterServiceInstance_401(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func Test_RegisterServiceInstance_CanceledContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer func() {
+		ctrl.Finish()
+	}()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any()).Times(0)
+
+	nc := nacos_client.NacosClient{}
+	_ = nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	_ = nc.SetClientConfig(clientConfigTest)
+	_ = nc.SetHttpAgent(mockIHttpAgent)
+	client, _ := NewNamingClient(&nc)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := client.RegisterInstanceWithContext(ctx, vo.RegisterInstanceParam{
+		ServiceName: "DEMO5",
+		Ip:          "10.0.0.10",
+		Port:        80,
+		GroupName:   "test_group",
+		Ephemeral:   false,
+	})
+	assert.Equal(t, false, result)
+	assert.Equal(t, context.Canceled, err)
+}
+
 func TestNamingProxy_DeregisterService_WithoutGroupName(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer func() {
@@ -216,7 +252,7 @@ func TestNamingProxy_DeregisterService_WithoutGroupName(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("DELETE"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("DELETE"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(10*1000)),
@@ -249,7 +285,7 @@ func TestNamingProxy_DeregisterService_WithGroupName(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("DELETE"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("DELETE"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(10*1000)),
@@ -282,7 +318,7 @@ func Test_UpdateServiceInstance_withoutGroupName(t *testing.T) {
 		ctrl.Finish()
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("PUT"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("PUT"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(10*1000)),
@@ -321,7 +357,7 @@ func TestNamingProxy_DeregisterService_401(t *testing.T) {
 	}()
 	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
 
-	mockIHttpAgent.EXPECT().Request(gomock.Eq("DELETE"),
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("DELETE"),
 		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
 		gomock.AssignableToTypeOf(http.Header{}),
 		gomock.Eq(uint64(10*1000)),
@@ -434,10 +470,10 @@ func TestNamingClient_SelectOneHealthyInstance_SameWeight(t *testing.T) {
 	_ = nc.SetClientConfig(clientConfigTest)
 	_ = nc.SetHttpAgent(mockIHttpAgent)
 	client, _ := NewNamingClient(&nc)
-	instance1, err := client.selectOneHealthyInstances(services)
+	instance1, err := client.selectOneHealthyInstances(services, false, nil, false, nil, "")
 	assert.Nil(t, err)
 	assert.NotNil(t, instance1)
-	instance2, err := client.selectOneHealthyInstances(services)
+	instance2, err := client.selectOneHealthyInstances(services, false, nil, false, nil, "")
 	assert.Nil(t, err)
 	assert.NotNil(t, instance2)
 }
@@ -462,7 +498,7 @@ func TestNamingClient_SelectOneHealthyInstance_Empty(t *testing.T) {
 	_ = nc.SetClientConfig(clientConfigTest)
 	_ = nc.SetHttpAgent(mockIHttpAgent)
 	client, _ := NewNamingClient(&nc)
-	instance, err := client.selectOneHealthyInstances(services)
+	instance, err := client.selectOneHealthyInstances(services, false, nil, false, nil, "")
 	assert.NotNil(t, err)
 	assert.Nil(t, instance)
 }
@@ -712,6 +748,414 @@ func TestNamingClient_selectOneHealthyInstanceResult(t *testing.T) {
 	_ = nc.SetClientConfig(clientConfigTest)
 	client, _ := NewNamingClient(&nc)
 	for i := 0; i < 10; i++ {
-		_, _ = client.selectOneHealthyInstances(services)
+		_, _ = client.selectOneHealthyInstances(services, false, nil, false, nil, "")
+	}
+}
+
+func TestNamingClient_SelectOneHealthyInstance_FractionalWeight(t *testing.T) {
+	services := model.Service{
+		Name:     "DEFAULT_GROUP@@DEMO",
+		Clusters: "a",
+		Hosts: []model.Instance{
+			{Ip: "127.0.0.1", Port: 80, Weight: 0.2, Enable: true, Healthy: true},
+			{Ip: "127.0.0.2", Port: 80, Weight: 0.5, Enable: true, Healthy: true},
+			{Ip: "127.0.0.3", Port: 80, Weight: 0.5, Enable: true, Healthy: false},
+		}}
+	nc := nacos_client.NacosClient{}
+	_ = nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	_ = nc.SetClientConfig(clientConfigTest)
+	client, _ := NewNamingClient(&nc)
+	for i := 0; i < 10; i++ {
+		instance, err := client.selectOneHealthyInstances(services, false, nil, false, nil, "")
+		assert.Nil(t, err)
+		assert.NotEqual(t, "127.0.0.3", instance.Ip)
 	}
+	// the hosts of the service are not reordered
+	assert.Equal(t, "127.0.0.1", services.Hosts[0].Ip)
+
+	// the balancer of the param takes priority over the one of the client
+	b := balancer.NewSmoothWeightedRoundRobin()
+	picks := map[string]int{}
+	for i := 0; i < 7; i++ {
+		instance, err := client.selectOneHealthyInstances(services, false, nil, false, b, "")
+		assert.Nil(t, err)
+		picks[instance.Ip]++
+	}
+	assert.Equal(t, map[string]int{"127.0.0.1": 2, "127.0.0.2": 5}, picks)
+}
+
+func TestNamingClient_CloseClient_DeregisterAtClose(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer func() {
+		ctrl.Finish()
+	}()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("POST"),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `ok`), nil)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("PUT"),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/beat"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		Return(http_agent.FakeHttpResponse(200, `{"clientBeatInterval":5000}`), nil)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("DELETE"),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `ok`), nil)
+
+	config := clientConfigTest
+	config.DeregisterAtClose = true
+	nc := nacos_client.NacosClient{}
+	_ = nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	_ = nc.SetClientConfig(config)
+	_ = nc.SetHttpAgent(mockIHttpAgent)
+	client, _ := NewNamingClient(&nc)
+	success, err := client.RegisterInstance(vo.RegisterInstanceParam{
+		ServiceName: "DEMO6",
+		Ip:          "10.0.0.10",
+		Port:        80,
+		Weight:      1,
+		Ephemeral:   true,
+	})
+	assert.Nil(t, err)
+	assert.True(t, success)
+	client.CloseClient()
+	assert.Equal(t, 0, client.beatReactor.beatMap.Count())
+}
+
+func TestNamingClient_WarmUpServices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("GET"),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/instance/list"),
+		gomock.Any(), gomock.Any(), gomock.Any()).MinTimes(1).
+		Return(http_agent.FakeHttpResponse(200, `{"name":"DEFAULT_GROUP@@DEMO","clusters":"a","cacheMillis":10000,`+
+			`"hosts":[{"ip":"10.0.0.10","port":80,"weight":1,"healthy":true,"enabled":true}],"lastRefTime":1}`), nil)
+
+	config := clientConfigTest
+	config.WarmUpServices = []constant.WarmUpService{{ServiceName: "DEMO", Clusters: []string{"a"}}}
+	nc := nacos_client.NacosClient{}
+	_ = nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	_ = nc.SetClientConfig(config)
+	_ = nc.SetHttpAgent(mockIHttpAgent)
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.CloseClient()
+	assert.True(t, client.Ready())
+	assert.Nil(t, client.WaitReady(context.Background()))
+	_, ok := client.hostReactor.serviceInfoMap.Get("DEFAULT_GROUP@@DEMO@@a")
+	assert.True(t, ok)
+
+	config.WarmUpServices = []constant.WarmUpService{{GroupName: "group"}}
+	_ = nc.SetClientConfig(config)
+	invalidClient, err := NewNamingClient(&nc)
+	assert.NotNil(t, err)
+	// the reactors are not started for the invalid client config
+	assert.Nil(t, invalidClient.hostReactor.wg)
+	assert.Nil(t, invalidClient.beatReactor.wg)
+}
+
+// cacheService puts the service into the cache of the host reactor, it's not updated from server in a minute
+func cacheService(client NamingClient, service model.Service) {
+	key := util.GetServiceCacheKey(service.Name, service.Clusters)
+	service.CacheMillis = 60 * 1000
+	client.hostReactor.updateTimeMap.Set(key, uint64(util.CurrentMillis()))
+	client.hostReactor.serviceInfoMap.Set(key, service)
+}
+
+func TestNamingClient_SelectInstances_Selector(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	nc := nacos_client.NacosClient{}
+	_ = nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	_ = nc.SetClientConfig(clientConfigTest)
+	_ = nc.SetHttpAgent(mock.NewMockIHttpAgent(ctrl))
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.CloseClient()
+	cacheService(client, model.Service{
+		Name:     "DEFAULT_GROUP@@DEMO",
+		Clusters: "a",
+		Hosts: []model.Instance{
+			{Ip: "10.0.0.1", Port: 80, Weight: 1, Enable: true, Healthy: true, Metadata: map[string]string{"version": "v1", "zone": "a"}},
+			{Ip: "10.0.0.2", Port: 80, Weight: 1, Enable: true, Healthy: true, Metadata: map[string]string{"version": "v2", "zone": "b"}},
+			{Ip: "10.0.0.3", Port: 80, Weight: 1, Enable: true, Healthy: false, Metadata: map[string]string{"version": "v3"}},
+		}})
+
+	selectIps := func(expr string, fallback bool) []string {
+		instances, err := client.SelectInstances(vo.SelectInstancesParam{
+			ServiceName:      "DEMO",
+			Clusters:         []string{"a"},
+			HealthyOnly:      true,
+			Selector:         expr,
+			SelectorFallback: fallback,
+		})
+		assert.Nil(t, err)
+		var ips []string
+		for _, instance := range instances {
+			ips = append(ips, instance.Ip)
+		}
+		return ips
+	}
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, selectIps("", false))
+	assert.Equal(t, []string{"10.0.0.2"}, selectIps("version=v2", false))
+	assert.Equal(t, []string{"10.0.0.1"}, selectIps("zone in (a,c),version!=v2", false))
+	// the only instance of v3 is unhealthy, so the selector falls back to all the healthy instances
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, selectIps("version=v3", true))
+
+	assert.Empty(t, selectIps("version=v3", false))
+	assert.Empty(t, selectIps("version=v4", false))
+
+	_, err = client.SelectInstances(vo.SelectInstancesParam{ServiceName: "DEMO", Clusters: []string{"a"}, Selector: "version in (v1"})
+	assert.NotNil(t, err)
+
+	for i := 0; i < 10; i++ {
+		instance, err := client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{
+			ServiceName: "DEMO",
+			Clusters:    []string{"a"},
+			Selector:    "zone=b",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "10.0.0.2", instance.Ip)
+	}
+	instance, err := client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{
+		ServiceName:      "DEMO",
+		Clusters:         []string{"a"},
+		Selector:         "version=v3",
+		SelectorFallback: true,
+	})
+	assert.Nil(t, err)
+	assert.NotEqual(t, "10.0.0.3", instance.Ip)
+	_, err = client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{
+		ServiceName: "DEMO",
+		Clusters:    []string{"a"},
+		Selector:    "version=v3",
+	})
+	assert.NotNil(t, err)
+}
+
+func TestNamingClient_SelectOneHealthyInstance_PreferLocalZone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := clientConfigTest
+	config.Zone = "a"
+	config.ZoneSpillThreshold = 0.6
+	nc := nacos_client.NacosClient{}
+	_ = nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	_ = nc.SetClientConfig(config)
+	_ = nc.SetHttpAgent(mock.NewMockIHttpAgent(ctrl))
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.CloseClient()
+
+	services := model.Service{
+		Name: "DEFAULT_GROUP@@DEMO",
+		Hosts: []model.Instance{
+			{Ip: "10.0.0.1", Port: 80, Weight: 1, Enable: true, Healthy: true, Metadata: map[string]string{"zone": "a"}},
+			{Ip: "10.0.0.2", Port: 80, Weight: 1, Enable: true, Healthy: true, Metadata: map[string]string{"zone": "a"}},
+			{Ip: "10.0.0.3", Port: 80, Weight: 1, Enable: true, Healthy: true, Metadata: map[string]string{"zone": "b"}},
+			{Ip: "10.0.0.4", Port: 80, Weight: 1, Enable: true, Healthy: true},
+		}}
+	pickZones := func() map[string]int {
+		zones := map[string]int{}
+		for i := 0; i < 100; i++ {
+			instance, err := client.selectOneHealthyInstances(services, false, nil, false, nil, "")
+			assert.Nil(t, err)
+			zones[instance.Metadata["zone"]]++
+		}
+		return zones
+	}
+	assert.Equal(t, map[string]int{"a": 100}, pickZones())
+
+	// the healthy weight ratio of zone a drops to 0.5, which is below the threshold
+	services.Hosts[1].Healthy = false
+	zones := pickZones()
+	assert.True(t, zones["b"] > 0 && zones[""] > 0)
+	services.Hosts[1].Weight = 0.5
+	assert.Equal(t, map[string]int{"a": 100}, pickZones())
+
+	client.zoneSpillThreshold = 0
+	services.Hosts[1].Weight = 100
+	assert.Equal(t, map[string]int{"a": 100}, pickZones())
+	services.Hosts[0].Healthy = false
+	zones = pickZones()
+	assert.Equal(t, 0, zones["a"])
+
+	// the locality preference is disabled without the zone of the client
+	services.Hosts[0].Healthy = true
+	client.zone = ""
+	zones = pickZones()
+	assert.True(t, zones["a"] > 0 && zones["b"] > 0)
+}
+
+func TestNamingClient_ProtectThreshold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("GET"),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/service"),
+		gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+		Return(http_agent.FakeHttpResponse(200, `{"namespaceId":"public","groupName":"DEFAULT_GROUP","name":"DEMO","protectThreshold":0.6}`), nil)
+
+	var events []model.ProtectState
+	config := clientConfigTest
+	config.UseProtectThreshold = true
+	config.OnProtectChange = func(state model.ProtectState) {
+		events = append(events, state)
+	}
+	nc := nacos_client.NacosClient{}
+	_ = nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	_ = nc.SetClientConfig(config)
+	_ = nc.SetHttpAgent(mockIHttpAgent)
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.CloseClient()
+	service := model.Service{
+		Name:     "DEFAULT_GROUP@@DEMO",
+		Clusters: "a",
+		Hosts: []model.Instance{
+			{Ip: "10.0.0.1", Port: 80, Weight: 1, Enable: true, Healthy: true},
+			{Ip: "10.0.0.2", Port: 80, Weight: 1, Enable: true, Healthy: false},
+			{Ip: "10.0.0.3", Port: 80, Weight: 1, Enable: true, Healthy: false},
+		}}
+	cacheService(client, service)
+	param := vo.SelectInstancesParam{ServiceName: "DEMO", Clusters: []string{"a"}, HealthyOnly: true}
+	// the protect threshold is loaded in background
+	assert.Eventually(t, func() bool { return client.protector.threshold("DEFAULT_GROUP@@DEMO") > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// 1 of the 3 instances is healthy, which is below the protect threshold 0.6
+	instances, err := client.SelectInstances(param)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(instances))
+	ips := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		instance, err := client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{ServiceName: "DEMO", Clusters: []string{"a"}})
+		assert.Nil(t, err)
+		ips[instance.Ip] = true
+	}
+	assert.Equal(t, 3, len(ips))
+	assert.Equal(t, 1, len(events))
+	assert.True(t, events[0].Protected)
+	assert.Equal(t, 0.6, events[0].ProtectThreshold)
+	states := client.GetProtectStates()
+	assert.Equal(t, 1, len(states))
+	assert.Equal(t, "DEFAULT_GROUP@@DEMO", states[0].ServiceName)
+	assert.InDelta(t, 1.0/3, states[0].HealthyRatio, 0.001)
+
+	// the protection mode ends when the healthy ratio recovers, the threshold is cached
+	service.Hosts[1].Healthy = true
+	cacheService(client, service)
+	instances, err = client.SelectInstances(param)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(instances))
+	assert.Equal(t, 2, len(events))
+	assert.False(t, events[1].Protected)
+	assert.False(t, client.GetProtectStates()[0].Protected)
+}
+
+func TestNamingClient_ProtectThresholdRefresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	block := make(chan struct{})
+	var queries int32
+	mockIHttpAgent := mock.NewMockIHttpAgent(ctrl)
+	mockIHttpAgent.EXPECT().RequestWithContext(gomock.Any(), gomock.Eq("GET"),
+		gomock.Eq("http://console.nacos.io:80/nacos/v1/ns/service"),
+		gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+			if atomic.AddInt32(&queries, 1) == 1 {
+				return http_agent.FakeHttpResponse(200, `{"name":"DEMO","protectThreshold":0.6}`), nil
+			}
+			<-block
+			return http_agent.FakeHttpResponse(500, "server error"), nil
+		})
+
+	config := clientConfigTest
+	config.UseProtectThreshold = true
+	nc := nacos_client.NacosClient{}
+	_ = nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	_ = nc.SetClientConfig(config)
+	_ = nc.SetHttpAgent(mockIHttpAgent)
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.CloseClient()
+	p := client.protector
+	assert.Eventually(t, func() bool { return p.threshold("DEFAULT_GROUP@@DEMO") == 0.6 }, 5*time.Second, 10*time.Millisecond)
+
+	// the expired threshold is refreshed in background, the slow query does not block and the last value is used
+	p.thresholds.Set("DEFAULT_GROUP@@DEMO", protectThreshold{value: 0.6, expireAt: time.Now().Add(-time.Second)})
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, 0.6, p.threshold("DEFAULT_GROUP@@DEMO"))
+	}
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&queries) == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0.6, p.threshold("DEFAULT_GROUP@@DEMO"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&queries))
+
+	// the failed query keeps the last value
+	close(block)
+	assert.Eventually(t, func() bool { return !p.refreshing.Has("DEFAULT_GROUP@@DEMO") }, 5*time.Second, 10*time.Millisecond)
+	cached, _ := p.thresholds.Get("DEFAULT_GROUP@@DEMO")
+	assert.Equal(t, 0.6, cached.(protectThreshold).value)
+	assert.True(t, cached.(protectThreshold).expireAt.After(time.Now()))
+}
+
+func TestNamingClient_ReportResult(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config := clientConfigTest
+	config.OutlierDetection = constant.OutlierDetection{ConsecutiveFailures: 2, MaxEjectionPercent: 50}
+	nc := nacos_client.NacosClient{}
+	_ = nc.SetServerConfig([]constant.ServerConfig{serverConfigTest})
+	_ = nc.SetClientConfig(config)
+	_ = nc.SetHttpAgent(mock.NewMockIHttpAgent(ctrl))
+	client, err := NewNamingClient(&nc)
+	assert.Nil(t, err)
+	defer client.CloseClient()
+	cacheService(client, model.Service{
+		Name:     "DEFAULT_GROUP@@DEMO",
+		Clusters: "a",
+		Hosts: []model.Instance{
+			{ServiceName: "DEFAULT_GROUP@@DEMO", Ip: "10.0.0.1", Port: 80, Weight: 1, Enable: true, Healthy: true,
+				Metadata: map[string]string{"version": "v1"}},
+			{ServiceName: "DEFAULT_GROUP@@DEMO", Ip: "10.0.0.2", Port: 80, Weight: 1, Enable: true, Healthy: true,
+				Metadata: map[string]string{"version": "v2"}},
+		}})
+	param := vo.SelectOneHealthInstanceParam{ServiceName: "DEMO", Clusters: []string{"a"}}
+
+	instance, err := client.SelectOneHealthyInstance(param)
+	assert.Nil(t, err)
+	client.ReportResult(*instance, errors.New("timeout"), time.Second)
+	client.ReportResult(*instance, errors.New("timeout"), time.Second)
+	for i := 0; i < 10; i++ {
+		other, err := client.SelectOneHealthyInstance(param)
+		assert.Nil(t, err)
+		assert.NotEqual(t, instance.Ip, other.Ip)
+	}
+	instances, err := client.SelectInstances(vo.SelectInstancesParam{ServiceName: "DEMO", Clusters: []string{"a"}, HealthyOnly: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(instances))
+
+	stats := client.GetOutlierStats()
+	assert.Equal(t, 1, len(stats))
+	assert.Equal(t, instance.Ip, stats[0].Ip)
+	assert.True(t, stats[0].Ejected)
+
+	// the ejected instances are filtered before the metadata selector in both selections
+	selector := "version=" + instance.Metadata["version"]
+	instances, err = client.SelectInstances(vo.SelectInstancesParam{ServiceName: "DEMO", Clusters: []string{"a"}, HealthyOnly: true,
+		Selector: selector})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(instances))
+	_, err = client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{ServiceName: "DEMO", Clusters: []string{"a"}, Selector: selector})
+	assert.NotNil(t, err)
+	instances, err = client.SelectInstances(vo.SelectInstancesParam{ServiceName: "DEMO", Clusters: []string{"a"}, HealthyOnly: true,
+		Selector: selector, SelectorFallback: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(instances))
+	assert.NotEqual(t, instance.Ip, instances[0].Ip)
+	other, err := client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{ServiceName: "DEMO", Clusters: []string{"a"},
+		Selector: selector, SelectorFallback: true})
+	assert.Nil(t, err)
+	assert.NotEqual(t, instance.Ip, other.Ip)
 }
@@ -17,6 +17,7 @@
 package naming_client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -37,12 +38,12 @@ type NamingProxy struct {
 	nacosServer  *nacos_server.NacosServer
 }
 
-func NewNamingProxy(clientCfg constant.ClientConfig, serverCfgs []constant.ServerConfig, httpAgent http_agent.IHttpAgent) (NamingProxy, error) {
+func NewNamingProxy(ctx context.Context, clientCfg constant.ClientConfig, serverCfgs []constant.ServerConfig, httpAgent http_agent.IHttpAgent) (NamingProxy, error) {
 	srvProxy := NamingProxy{}
 	srvProxy.clientConfig = clientCfg
 
 	var err error
-	srvProxy.nacosServer, err = nacos_server.NewNacosServer(serverCfgs, clientCfg, httpAgent, clientCfg.TimeoutMs, clientCfg.Endpoint)
+	srvProxy.nacosServer, err = nacos_server.NewNacosServer(ctx, serverCfgs, clientCfg, httpAgent, clientCfg.TimeoutMs, clientCfg.Endpoint)
 	if err != nil {
 		return srvProxy, err
 	}
@@ -50,7 +51,7 @@ func NewNamingProxy(clientCfg constant.ClientConfig, serverCfgs []constant.Serve
 	return srvProxy, nil
 }
 
-func (proxy *NamingProxy) RegisterInstance(serviceName string, groupName string, instance model.Instance) (string, error) {
+func (proxy *NamingProxy) RegisterInstance(ctx context.Context, serviceName string, groupName string, instance model.Instance) (string, error) {
 	logger.Infof("register instance namespaceId:<%s>,serviceName:<%s> with instance:<%s>",
 		proxy.clientConfig.NamespaceId, serviceName, util.ToJsonString(instance))
 	params := map[string]string{}
@@ -66,10 +67,10 @@ func (proxy *NamingProxy) RegisterInstance(serviceName string, groupName string,
 	params["healthy"] = strconv.FormatBool(instance.Healthy)
 	params["metadata"] = util.ToJsonString(instance.Metadata)
 	params["ephemeral"] = strconv.FormatBool(instance.Ephemeral)
-	return proxy.nacosServer.ReqApi(constant.SERVICE_PATH, params, http.MethodPost, proxy.getSecurityMap())
+	return proxy.nacosServer.ReqApiWithContext(ctx, constant.SERVICE_PATH, params, http.MethodPost, proxy.getSecurityMap())
 }
 
-func (proxy *NamingProxy) DeregisterInstance(serviceName string, ip string, port uint64, clusterName string, ephemeral bool) (string, error) {
+func (proxy *NamingProxy) DeregisterInstance(ctx context.Context, serviceName string, ip string, port uint64, clusterName string, ephemeral bool) (string, error) {
 	logger.Infof("deregister instance namespaceId:<%s>,serviceName:<%s> with instance:<%s:%d@%s>",
 		proxy.clientConfig.NamespaceId, serviceName, ip, port, clusterName)
 	params := map[string]string{}
@@ -79,10 +80,10 @@ func (proxy *NamingProxy) DeregisterInstance(serviceName string, ip string, port
 	params["ip"] = ip
 	params["port"] = strconv.Itoa(int(port))
 	params["ephemeral"] = strconv.FormatBool(ephemeral)
-	return proxy.nacosServer.ReqApi(constant.SERVICE_PATH, params, http.MethodDelete, proxy.getSecurityMap())
+	return proxy.nacosServer.ReqApiWithContext(ctx, constant.SERVICE_PATH, params, http.MethodDelete, proxy.getSecurityMap())
 }
 
-func (proxy *NamingProxy) UpdateInstance(serviceName string, ip string, port uint64, clusterName string, ephemeral bool, weight float64, enable bool, metadata map[string]string) (string, error) {
+func (proxy *NamingProxy) UpdateInstance(ctx context.Context, serviceName string, ip string, port uint64, clusterName string, ephemeral bool, weight float64, enable bool, metadata map[string]string) (string, error) {
 	logger.Infof("modify instance namespaceId:<%s>,serviceName:<%s> with instance:<%s:%d@%s>",
 		proxy.clientConfig.NamespaceId, serviceName, ip, port, clusterName)
 	params := map[string]string{}
@@ -95,10 +96,10 @@ func (proxy *NamingProxy) UpdateInstance(serviceName string, ip string, port uin
 	params["weight"] = strconv.FormatFloat(weight, 'f', -1, 64)
 	params["enable"] = strconv.FormatBool(enable)
 	params["metadata"] = util.ToJsonString(metadata)
-	return proxy.nacosServer.ReqApi(constant.SERVICE_PATH, params, http.MethodPut, proxy.getSecurityMap())
+	return proxy.nacosServer.ReqApiWithContext(ctx, constant.SERVICE_PATH, params, http.MethodPut, proxy.getSecurityMap())
 }
 
-func (proxy *NamingProxy) SendBeat(info *model.BeatInfo) (int64, error) {
+func (proxy *NamingProxy) SendBeat(ctx context.Context, info *model.BeatInfo) (int64, error) {
 
 	logger.Infof("namespaceId:<%s> sending beat to server:<%s>",
 		proxy.clientConfig.NamespaceId, util.ToJsonString(info))
@@ -107,7 +108,7 @@ func (proxy *NamingProxy) SendBeat(info *model.BeatInfo) (int64, error) {
 	params["serviceName"] = info.ServiceName
 	params["beat"] = util.ToJsonString(info)
 	api := constant.SERVICE_BASE_PATH + "/instance/beat"
-	result, err := proxy.nacosServer.ReqApi(api, params, http.MethodPut, proxy.getSecurityMap())
+	result, err := proxy.nacosServer.ReqApiWithContext(ctx, api, params, http.MethodPut, proxy.getSecurityMap())
 	if err != nil {
 		return 0, err
 	}
@@ -123,7 +124,7 @@ func (proxy *NamingProxy) SendBeat(info *model.BeatInfo) (int64, error) {
 
 }
 
-func (proxy *NamingProxy) GetServiceList(pageNo int, pageSize int, groupName string, selector *model.ExpressionSelector) (*model.ServiceList, error) {
+func (proxy *NamingProxy) GetServiceList(ctx context.Context, pageNo int, pageSize int, groupName string, selector *model.ExpressionSelector) (*model.ServiceList, error) {
 	params := map[string]string{}
 	params["namespaceId"] = proxy.clientConfig.NamespaceId
 	params["groupName"] = groupName
@@ -138,7 +139,7 @@ func (proxy *NamingProxy) GetServiceList(pageNo int, pageSize int, groupName str
 	}
 
 	api := constant.SERVICE_BASE_PATH + "/service/list"
-	result, err := proxy.nacosServer.ReqApi(api, params, http.MethodGet, proxy.getSecurityMap())
+	result, err := proxy.nacosServer.ReqApiWithContext(ctx, api, params, http.MethodGet, proxy.getSecurityMap())
 	if err != nil {
 		return nil, err
 	}
@@ -163,9 +164,9 @@ func (proxy *NamingProxy) GetServiceList(pageNo int, pageSize int, groupName str
 	return &serviceList, nil
 }
 
-func (proxy *NamingProxy) ServerHealthy() bool {
+func (proxy *NamingProxy) ServerHealthy(ctx context.Context) bool {
 	api := constant.SERVICE_BASE_PATH + "/operator/metrics"
-	result, err := proxy.nacosServer.ReqApi(api, map[string]string{}, http.MethodGet, proxy.getSecurityMap())
+	result, err := proxy.nacosServer.ReqApiWithContext(ctx, api, map[string]string{}, http.MethodGet, proxy.getSecurityMap())
 	if err != nil {
 		logger.Errorf("namespaceId:[%s] sending server healthy failed!,result:%s error:%+v", proxy.clientConfig.NamespaceId, result, err)
 		return false
@@ -181,7 +182,7 @@ func (proxy *NamingProxy) ServerHealthy() bool {
 	return false
 }
 
-func (proxy *NamingProxy) QueryList(serviceName string, clusters string, udpPort int, healthyOnly bool) (string, error) {
+func (proxy *NamingProxy) QueryList(ctx context.Context, serviceName string, clusters string, udpPort int, healthyOnly bool) (string, error) {
 	param := make(map[string]string)
 	param["namespaceId"] = proxy.clientConfig.NamespaceId
 	param["serviceName"] = serviceName
@@ -191,17 +192,25 @@ func (proxy *NamingProxy) QueryList(serviceName string, clusters string, udpPort
 	param["healthyOnly"] = strconv.FormatBool(healthyOnly)
 	param["clientIP"] = util.LocalIP()
 	api := constant.SERVICE_PATH + "/list"
-	return proxy.nacosServer.ReqApi(api, param, http.MethodGet, proxy.getSecurityMap())
+	return proxy.nacosServer.ReqApiWithContext(ctx, api, param, http.MethodGet, proxy.getSecurityMap())
 }
 
-func (proxy *NamingProxy) GetAllServiceInfoList(namespace, groupName string, pageNo, pageSize uint32) (string, error) {
+// QueryService queries the detail of the service, such as its protect threshold
+func (proxy *NamingProxy) QueryService(ctx context.Context, serviceName string) (string, error) {
+	param := make(map[string]string)
+	param["namespaceId"] = proxy.clientConfig.NamespaceId
+	param["serviceName"] = serviceName
+	return proxy.nacosServer.ReqApiWithContext(ctx, constant.SERVICE_INFO_PATH, param, http.MethodGet, proxy.getSecurityMap())
+}
+
+func (proxy *NamingProxy) GetAllServiceInfoList(ctx context.Context, namespace, groupName string, pageNo, pageSize uint32) (string, error) {
 	param := make(map[string]string)
 	param["namespaceId"] = namespace
 	param["groupName"] = groupName
 	param["pageNo"] = strconv.Itoa(int(pageNo))
 	param["pageSize"] = strconv.Itoa(int(pageSize))
 	api := constant.SERVICE_INFO_PATH + "/list"
-	return proxy.nacosServer.ReqApi(api, param, http.MethodGet, proxy.getSecurityMap())
+	return proxy.nacosServer.ReqApiWithContext(ctx, api, param, http.MethodGet, proxy.getSecurityMap())
 }
 
 func (proxy *NamingProxy) getSecurityMap() map[string]string {
@@ -213,12 +222,12 @@ func (proxy *NamingProxy) getSecurityMap() map[string]string {
 	return result
 }
 
-func (proxy *NamingProxy) GetCatalogServiceList(namespace string, pageNo, pageSize uint32) (string,
+func (proxy *NamingProxy) GetCatalogServiceList(ctx context.Context, namespace string, pageNo, pageSize uint32) (string,
 	error) {
 	param := make(map[string]string)
 	param["namespaceId"] = namespace
 	param["pageNo"] = strconv.Itoa(int(pageNo))
 	param["pageSize"] = strconv.Itoa(int(pageSize))
 	api := constant.CATALOG_SERVICE_PATH
-	return proxy.nacosServer.ReqApi(api, param, http.MethodGet, proxy.getSecurityMap())
+	return proxy.nacosServer.ReqApiWithContext(ctx, api, param, http.MethodGet, proxy.getSecurityMap())
 }
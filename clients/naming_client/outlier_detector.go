@@ -0,0 +1,268 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package naming_client
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/constant"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+)
+
+const (
+	defaultConsecutiveFailures = 5
+	defaultErrorRate           = 0.5
+	defaultMinRequests         = 10
+	defaultIntervalMs          = 10 * 1000
+	defaultBaseEjectionTimeMs  = 30 * 1000
+	defaultMaxEjectionTimeMs   = 300 * 1000
+	defaultMaxEjectionPercent  = 10
+)
+
+type instanceOutlier struct {
+	stats       model.OutlierStats
+	windowStart time.Time
+	latency     time.Duration // the total latency of the results in the current interval
+	lastReport  time.Time
+}
+
+// outlierDetector ejects the instances with consecutive failures or a high error rate from the selection, the
+// ejection time doubles on each recent ejection, and decays by one ejection for each interval without an ejection
+type outlierDetector struct {
+	consecutiveFailures int
+	errorRate           float64
+	minRequests         int
+	interval            time.Duration
+	baseEjectionTime    time.Duration
+	maxEjectionTime     time.Duration
+	maxEjectionPercent  int
+
+	mux       sync.Mutex
+	instances map[string]*instanceOutlier // the service name@@ip:port -> instanceOutlier
+	hostCount map[string]int              // the service name -> the count of its healthy instances last selected from
+	lastSweep time.Time
+}
+
+func newOutlierDetector(config constant.OutlierDetection) *outlierDetector {
+	d := &outlierDetector{
+		consecutiveFailures: config.ConsecutiveFailures,
+		errorRate:           config.ErrorRate,
+		minRequests:         config.MinRequests,
+		interval:            time.Duration(config.IntervalMs) * time.Millisecond,
+		baseEjectionTime:    time.Duration(config.BaseEjectionTimeMs) * time.Millisecond,
+		maxEjectionTime:     time.Duration(config.MaxEjectionTimeMs) * time.Millisecond,
+		maxEjectionPercent:  config.MaxEjectionPercent,
+		instances:           map[string]*instanceOutlier{},
+		hostCount:           map[string]int{},
+		lastSweep:           time.Now(),
+	}
+	if d.consecutiveFailures <= 0 {
+		d.consecutiveFailures = defaultConsecutiveFailures
+	}
+	if d.errorRate <= 0 {
+		d.errorRate = defaultErrorRate
+	}
+	if d.minRequests <= 0 {
+		d.minRequests = defaultMinRequests
+	}
+	if d.interval <= 0 {
+		d.interval = defaultIntervalMs * time.Millisecond
+	}
+	if d.baseEjectionTime <= 0 {
+		d.baseEjectionTime = defaultBaseEjectionTimeMs * time.Millisecond
+	}
+	if d.maxEjectionTime <= 0 {
+		d.maxEjectionTime = defaultMaxEjectionTimeMs * time.Millisecond
+	}
+	if d.maxEjectionTime < d.baseEjectionTime {
+		d.maxEjectionTime = d.baseEjectionTime
+	}
+	if d.maxEjectionPercent <= 0 {
+		d.maxEjectionPercent = defaultMaxEjectionPercent
+	}
+	return d
+}
+
+func outlierKey(instance model.Instance) string {
+	return instance.ServiceName + constant.SERVICE_INFO_SPLITER + instance.Ip + ":" + strconv.FormatUint(instance.Port, 10)
+}
+
+// report records the result of a call to the instance, and ejects it when it becomes an outlier.
+// The results of the calls finished during the ejection are ignored
+func (d *outlierDetector) report(instance model.Instance, err error, latency time.Duration) {
+	now := time.Now()
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.sweep(now)
+	key := outlierKey(instance)
+	o, ok := d.instances[key]
+	if !ok {
+		o = &instanceOutlier{
+			stats:       model.OutlierStats{ServiceName: instance.ServiceName, Ip: instance.Ip, Port: instance.Port},
+			windowStart: now,
+		}
+		d.instances[key] = o
+	}
+	o.lastReport = now
+	if now.Before(o.stats.EjectedUntil) {
+		return
+	}
+	if now.Sub(o.windowStart) >= d.interval {
+		o.stats.Successes, o.stats.Failures, o.latency = 0, 0, 0
+		o.windowStart = now
+	}
+	o.latency += latency
+	if err == nil {
+		o.stats.Successes++
+		o.stats.ConsecutiveFailures = 0
+	} else {
+		o.stats.Failures++
+		o.stats.ConsecutiveFailures++
+	}
+	o.stats.AvgLatency = o.latency / time.Duration(o.stats.Successes+o.stats.Failures)
+	if err == nil {
+		return
+	}
+	requests := o.stats.Successes + o.stats.Failures
+	if o.stats.ConsecutiveFailures >= d.consecutiveFailures ||
+		(requests >= d.minRequests && float64(o.stats.Failures)/float64(requests) >= d.errorRate) {
+		d.eject(o, now)
+	}
+}
+
+func (d *outlierDetector) eject(o *instanceOutlier, now time.Time) {
+	total, ejected := d.hostCount[o.stats.ServiceName], 0
+	tracked := 0
+	for _, other := range d.instances {
+		if other.stats.ServiceName == o.stats.ServiceName {
+			tracked++
+			if now.Before(other.stats.EjectedUntil) {
+				ejected++
+			}
+		}
+	}
+	if tracked > total {
+		total = tracked
+	}
+	allowed := total * d.maxEjectionPercent / 100
+	if allowed < 1 {
+		allowed = 1
+	}
+	if ejected >= allowed {
+		logger.Debugf("instance %s:%d of service:%s is an outlier, but %d of %d instances are ejected already",
+			o.stats.Ip, o.stats.Port, o.stats.ServiceName, ejected, total)
+		return
+	}
+	// the ejection count decays by one for each interval since the last ejection ended
+	if !o.stats.EjectedUntil.IsZero() {
+		o.stats.EjectionCount -= int(now.Sub(o.stats.EjectedUntil) / d.interval)
+		if o.stats.EjectionCount < 0 {
+			o.stats.EjectionCount = 0
+		}
+	}
+	ejectionTime := d.baseEjectionTime
+	for i := 0; i < o.stats.EjectionCount && ejectionTime < d.maxEjectionTime; i++ {
+		ejectionTime *= 2
+	}
+	if ejectionTime > d.maxEjectionTime {
+		ejectionTime = d.maxEjectionTime
+	}
+	o.stats.EjectionCount++
+	o.stats.EjectedUntil = now.Add(ejectionTime)
+	// the instance starts over when it comes back
+	o.stats.Successes, o.stats.Failures, o.stats.ConsecutiveFailures, o.latency = 0, 0, 0, 0
+	o.windowStart = o.stats.EjectedUntil
+	logger.Warnf("instance %s:%d of service:%s is ejected for %s", o.stats.Ip, o.stats.Port, o.stats.ServiceName, ejectionTime)
+}
+
+// sweep forgets the instances not reported in maxEjectionTime, such as the ones deregistered, once in maxEjectionTime
+func (d *outlierDetector) sweep(now time.Time) {
+	if now.Sub(d.lastSweep) < d.maxEjectionTime {
+		return
+	}
+	d.lastSweep = now
+	for key, o := range d.instances {
+		if now.Sub(o.lastReport) >= d.maxEjectionTime && !now.Before(o.stats.EjectedUntil) {
+			delete(d.instances, key)
+		}
+	}
+}
+
+// filter removes the ejected instances from the candidates selected from the hosts of the service, all the candidates
+// are kept when they are all ejected. The healthy hosts are counted for the max ejection percent, whatever subset of
+// them the candidates are. A nil detector filters nothing
+func (d *outlierDetector) filter(hosts []model.Instance, candidates []model.Instance) []model.Instance {
+	if d == nil || len(candidates) == 0 {
+		return candidates
+	}
+	now := time.Now()
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	hostCount := map[string]int{}
+	for _, host := range hosts {
+		if usableInstance(host, true) {
+			hostCount[host.ServiceName]++
+		}
+	}
+	for serviceName, count := range hostCount {
+		d.hostCount[serviceName] = count
+	}
+	if len(d.instances) == 0 {
+		return candidates
+	}
+	var result []model.Instance
+	for _, instance := range candidates {
+		if o, ok := d.instances[outlierKey(instance)]; ok && now.Before(o.stats.EjectedUntil) {
+			continue
+		}
+		result = append(result, instance)
+	}
+	if len(result) == 0 {
+		return candidates
+	}
+	return result
+}
+
+// outlierStats returns the states of the reported instances, sorted by the service and address
+func (d *outlierDetector) outlierStats() []model.OutlierStats {
+	if d == nil {
+		return nil
+	}
+	now := time.Now()
+	d.mux.Lock()
+	stats := make([]model.OutlierStats, 0, len(d.instances))
+	for _, o := range d.instances {
+		s := o.stats
+		s.Ejected = now.Before(s.EjectedUntil)
+		stats = append(stats, s)
+	}
+	d.mux.Unlock()
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].ServiceName != stats[j].ServiceName {
+			return stats[i].ServiceName < stats[j].ServiceName
+		}
+		if stats[i].Ip != stats[j].Ip {
+			return stats[i].Ip < stats[j].Ip
+		}
+		return stats[i].Port < stats[j].Port
+	})
+	return stats
+}
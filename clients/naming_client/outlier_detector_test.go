@@ -0,0 +1,134 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package naming_client
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/constant"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+)
+
+var errCall = errors.New("call failed")
+
+func newOutlierInstances(n int) []model.Instance {
+	instances := make([]model.Instance, n)
+	for i := range instances {
+		instances[i] = model.Instance{ServiceName: "DEFAULT_GROUP@@DEMO", Ip: "10.0.0." + strconv.Itoa(i+1), Port: 80, Weight: 1, Enable: true, Healthy: true}
+	}
+	return instances
+}
+
+func TestOutlierDetector_ConsecutiveFailures(t *testing.T) {
+	d := newOutlierDetector(constant.OutlierDetection{ConsecutiveFailures: 3, BaseEjectionTimeMs: 100, MaxEjectionTimeMs: 300, MaxEjectionPercent: 50})
+	instances := newOutlierInstances(4)
+	assert.Equal(t, 4, len(d.filter(instances, instances)))
+
+	d.report(instances[0], errCall, time.Millisecond)
+	d.report(instances[0], errCall, time.Millisecond)
+	d.report(instances[0], nil, time.Millisecond)
+	d.report(instances[0], errCall, time.Millisecond)
+	d.report(instances[0], errCall, time.Millisecond)
+	assert.Equal(t, 4, len(d.filter(instances, instances)))
+	d.report(instances[0], errCall, 4*time.Millisecond)
+	filtered := d.filter(instances, instances)
+	assert.Equal(t, 3, len(filtered))
+	assert.NotContains(t, filtered, instances[0])
+
+	stats := d.outlierStats()
+	assert.Equal(t, 1, len(stats))
+	assert.True(t, stats[0].Ejected)
+	assert.Equal(t, 1, stats[0].EjectionCount)
+	assert.Equal(t, "10.0.0.1", stats[0].Ip)
+	assert.InDelta(t, float64(100*time.Millisecond), float64(time.Until(stats[0].EjectedUntil)), float64(50*time.Millisecond))
+
+	// the results during the ejection are ignored
+	d.report(instances[0], errCall, time.Millisecond)
+	assert.Equal(t, 0, d.outlierStats()[0].Failures)
+
+	// the ejection time doubles on the next ejection
+	time.Sleep(110 * time.Millisecond)
+	assert.Equal(t, 4, len(d.filter(instances, instances)))
+	for i := 0; i < 3; i++ {
+		d.report(instances[0], errCall, time.Millisecond)
+	}
+	stats = d.outlierStats()
+	assert.Equal(t, 2, stats[0].EjectionCount)
+	assert.InDelta(t, float64(200*time.Millisecond), float64(time.Until(stats[0].EjectedUntil)), float64(50*time.Millisecond))
+}
+
+func TestOutlierDetector_ErrorRate(t *testing.T) {
+	d := newOutlierDetector(constant.OutlierDetection{ErrorRate: 0.5, MinRequests: 10, MaxEjectionPercent: 100})
+	instances := newOutlierInstances(2)
+	for i := 0; i < 9; i++ {
+		var err error
+		if i%2 == 1 {
+			err = errCall
+		}
+		d.report(instances[1], err, 10*time.Millisecond)
+	}
+	stats := d.outlierStats()
+	assert.False(t, stats[0].Ejected)
+	assert.Equal(t, 5, stats[0].Successes)
+	assert.Equal(t, 4, stats[0].Failures)
+	assert.Equal(t, 10*time.Millisecond, stats[0].AvgLatency)
+	d.report(instances[1], errCall, 10*time.Millisecond)
+	assert.True(t, d.outlierStats()[0].Ejected)
+	assert.Equal(t, []model.Instance{instances[0]}, d.filter(instances, instances))
+
+	// the candidates are kept when they are all ejected
+	for i := 0; i < defaultConsecutiveFailures; i++ {
+		d.report(instances[0], errCall, time.Millisecond)
+	}
+	assert.True(t, d.outlierStats()[0].Ejected)
+	assert.Equal(t, instances, d.filter(instances, instances))
+}
+
+func TestOutlierDetector_MaxEjectionPercent(t *testing.T) {
+	d := newOutlierDetector(constant.OutlierDetection{ConsecutiveFailures: 1, MaxEjectionPercent: 50})
+	instances := newOutlierInstances(4)
+	d.filter(instances, instances)
+	for _, instance := range instances {
+		d.report(instance, errCall, time.Millisecond)
+	}
+	assert.Equal(t, 2, len(d.filter(instances, instances)))
+
+	// one instance can always be ejected
+	d = newOutlierDetector(constant.OutlierDetection{ConsecutiveFailures: 1})
+	d.filter(instances, instances)
+	for _, instance := range instances {
+		d.report(instance, errCall, time.Millisecond)
+	}
+	assert.Equal(t, 3, len(d.filter(instances, instances)))
+
+	// the healthy hosts of the service are counted rather than the candidates
+	d = newOutlierDetector(constant.OutlierDetection{ConsecutiveFailures: 1, MaxEjectionPercent: 50})
+	d.filter(instances, instances[:1])
+	for _, instance := range instances[:2] {
+		d.report(instance, errCall, time.Millisecond)
+	}
+	assert.Equal(t, 2, len(d.filter(instances, instances)))
+
+	var nilDetector *outlierDetector
+	assert.Equal(t, instances, nilDetector.filter(instances, instances))
+	assert.Nil(t, nilDetector.outlierStats())
+}
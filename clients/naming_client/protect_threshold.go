@@ -0,0 +1,176 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package naming_client
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/clients/cache"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
+)
+
+const (
+	// protectThresholdTTL is how long the protect threshold of a service queried from server is cached
+	protectThresholdTTL = 30 * time.Second
+	// protectThresholdRetryDelay is how long the last known protect threshold is kept before retrying a failed query
+	protectThresholdRetryDelay = 5 * time.Second
+)
+
+type protectThreshold struct {
+	value    float64
+	expireAt time.Time
+}
+
+// protector honours the protect threshold of the services like the server does: when the healthy ratio of a service
+// is not above its protect threshold, the unhealthy instances are selected too, so that the traffic is not piled
+// onto the few healthy ones
+type protector struct {
+	ctx          context.Context
+	wg           sync.WaitGroup
+	serviceProxy NamingProxy
+	thresholds   cache.ConcurrentMap // the grouped service name -> protectThreshold
+	refreshing   cache.ConcurrentMap // the grouped service names whose protect threshold is being queried
+	states       cache.ConcurrentMap // the service cache key -> model.ProtectState
+	listener     model.ProtectListener
+}
+
+func newProtector(ctx context.Context, serviceProxy NamingProxy, listener model.ProtectListener) *protector {
+	return &protector{
+		ctx:          ctx,
+		serviceProxy: serviceProxy,
+		thresholds:   cache.NewConcurrentMap(),
+		refreshing:   cache.NewConcurrentMap(),
+		states:       cache.NewConcurrentMap(),
+		listener:     listener,
+	}
+}
+
+// check reports whether the service is in the protection mode, the listener is called when the mode changes.
+// A nil protector never protects
+func (p *protector) check(service model.Service) bool {
+	if p == nil || len(service.Hosts) == 0 {
+		return false
+	}
+	threshold := p.threshold(service.Name)
+	if threshold <= 0 {
+		return false
+	}
+	healthy := 0
+	for _, host := range service.Hosts {
+		if host.Healthy {
+			healthy++
+		}
+	}
+	state := model.ProtectState{
+		ServiceName:      service.Name,
+		Clusters:         service.Clusters,
+		ProtectThreshold: threshold,
+		HealthyRatio:     float64(healthy) / float64(len(service.Hosts)),
+	}
+	state.Protected = state.HealthyRatio <= threshold
+	changed := false
+	p.states.Upsert(util.GetServiceCacheKey(service.Name, service.Clusters), state, func(exist bool, valueInMap interface{}, newValue interface{}) interface{} {
+		changed = (exist && valueInMap.(model.ProtectState).Protected != state.Protected) || (!exist && state.Protected)
+		return newValue
+	})
+	if changed {
+		if state.Protected {
+			logger.Warnf("protect threshold reached, select all the instances, service:%s clusters:%s healthy ratio:%.2f threshold:%.2f",
+				service.Name, service.Clusters, state.HealthyRatio, threshold)
+		} else {
+			logger.Infof("protect threshold recovered, service:%s clusters:%s healthy ratio:%.2f threshold:%.2f",
+				service.Name, service.Clusters, state.HealthyRatio, threshold)
+		}
+		if p.listener != nil {
+			p.listener(state)
+		}
+	}
+	return state.Protected
+}
+
+// threshold returns the last known protect threshold of the service without blocking, 0 before the first query
+// succeeds. An expired threshold is refreshed from server in background, at most one query per service at a time
+func (p *protector) threshold(serviceName string) float64 {
+	var value float64
+	cached, ok := p.thresholds.Get(serviceName)
+	if ok {
+		value = cached.(protectThreshold).value
+		if time.Now().Before(cached.(protectThreshold).expireAt) {
+			return value
+		}
+	}
+	if p.ctx.Err() == nil && p.refreshing.SetIfAbsent(serviceName, true) {
+		p.wg.Add(1)
+		go p.refresh(serviceName)
+	}
+	return value
+}
+
+// refresh queries the protect threshold of the service from server, the last known value is kept when the query fails
+// and the query is retried after protectThresholdRetryDelay
+func (p *protector) refresh(serviceName string) {
+	defer p.wg.Done()
+	defer p.refreshing.Remove(serviceName)
+	result, err := p.serviceProxy.QueryService(p.ctx, serviceName)
+	var info model.ServiceInfo
+	if err == nil {
+		err = json.Unmarshal([]byte(result), &info)
+	}
+	if err == nil {
+		p.thresholds.Set(serviceName, protectThreshold{value: info.ProtectThreshold, expireAt: time.Now().Add(protectThresholdTTL)})
+		return
+	}
+	threshold := protectThreshold{expireAt: time.Now().Add(protectThresholdRetryDelay)}
+	if cached, ok := p.thresholds.Get(serviceName); ok {
+		threshold.value = cached.(protectThreshold).value
+	}
+	p.thresholds.Set(serviceName, threshold)
+	if p.ctx.Err() == nil {
+		logger.Warnf("query the protect threshold of service:%s failed, keep the last value:%.2f, err:%+v", serviceName, threshold.value, err)
+	}
+}
+
+// wait waits for the in-flight protect threshold queries, a nil protector has none
+func (p *protector) wait() {
+	if p != nil {
+		p.wg.Wait()
+	}
+}
+
+// protectStates returns the protect threshold states of the selected services, sorted by the service and clusters
+func (p *protector) protectStates() []model.ProtectState {
+	if p == nil {
+		return nil
+	}
+	var states []model.ProtectState
+	for _, state := range p.states.Items() {
+		states = append(states, state.(model.ProtectState))
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].ServiceName != states[j].ServiceName {
+			return states[i].ServiceName < states[j].ServiceName
+		}
+		return states[i].Clusters < states[j].Clusters
+	})
+	return states
+}
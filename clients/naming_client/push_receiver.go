@@ -19,6 +19,7 @@ package naming_client
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"math/rand"
@@ -31,6 +32,7 @@ import (
 )
 
 type PushReceiver struct {
+	ctx         context.Context
 	port        int
 	host        string
 	hostReactor *HostReactor
@@ -46,8 +48,9 @@ var (
 	GZIP_MAGIC = []byte("\x1F\x8B")
 )
 
-func NewPushReceiver(hostReactor *HostReactor) *PushReceiver {
+func NewPushReceiver(ctx context.Context, hostReactor *HostReactor) *PushReceiver {
 	pr := PushReceiver{
+		ctx:         ctx,
 		hostReactor: hostReactor,
 	}
 	pr.startServer()
@@ -93,9 +96,17 @@ func (us *PushReceiver) startServer() {
 	if conn == nil {
 		return
 	}
+	us.hostReactor.wg.Add(1)
 	go func() {
+		defer us.hostReactor.wg.Done()
+		<-us.ctx.Done()
+		conn.Close()
+	}()
+	us.hostReactor.wg.Add(1)
+	go func() {
+		defer us.hostReactor.wg.Done()
 		defer conn.Close()
-		for {
+		for us.ctx.Err() == nil {
 			us.handleClient(conn)
 		}
 	}()
@@ -114,6 +125,9 @@ func (us *PushReceiver) handleClient(conn *net.UDPConn) {
 	data := make([]byte, 4024)
 	n, remoteAddr, err := conn.ReadFromUDP(data)
 	if err != nil {
+		if us.ctx.Err() != nil {
+			return
+		}
 		logger.Errorf("failed to read UDP msg because of %+v", err)
 		return
 	}
@@ -18,23 +18,41 @@ package naming_client
 
 import (
 	"errors"
+	"sync"
 
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/clients/cache"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/selector"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
 )
 
 type SubscribeCallback struct {
 	callbackFuncsMap cache.ConcurrentMap
+	// filters holds the metadata selectors of the callbacks subscribed with one, keyed by the callback
+	filters *sync.Map
+}
+
+type subscribeFilter struct {
+	selector *selector.Selector
+	fallback bool
 }
 
 func NewSubscribeCallback() SubscribeCallback {
 	ed := SubscribeCallback{}
 	ed.callbackFuncsMap = cache.NewConcurrentMap()
+	ed.filters = &sync.Map{}
 	return ed
 }
 
+// AddSelectorCallbackFuncs is the same as AddCallbackFuncs, the callback is only called back with the hosts matching sel
+func (ed *SubscribeCallback) AddSelectorCallbackFuncs(serviceName string, clusters string, callbackFunc *func(services []model.SubscribeService, err error), sel *selector.Selector, fallback bool) {
+	if sel != nil && !sel.Empty() {
+		ed.filters.Store(callbackFunc, subscribeFilter{selector: sel, fallback: fallback})
+	}
+	ed.AddCallbackFuncs(serviceName, clusters, callbackFunc)
+}
+
 func (ed *SubscribeCallback) AddCallbackFuncs(serviceName string, clusters string, callbackFunc *func(services []model.SubscribeService, err error)) {
 	logger.Info("adding " + serviceName + " with " + clusters + " to listener map")
 	key := util.GetServiceCacheKey(serviceName, clusters)
@@ -60,6 +78,7 @@ func (ed *SubscribeCallback) RemoveCallbackFuncs(serviceName string, clusters st
 		}
 		ed.callbackFuncsMap.Set(key, newFuncs)
 	}
+	ed.filters.Delete(callbackFunc)
 
 }
 
@@ -76,7 +95,16 @@ func (ed *SubscribeCallback) ServiceChanged(service *model.Service) {
 				(*funcItem)(subscribeServices, errors.New("[client.Subscribe] subscribe failed,hosts is empty"))
 				return
 			}
-			for _, host := range service.Hosts {
+			hosts := service.Hosts
+			if filter, ok := ed.filters.Load(funcItem); ok {
+				f := filter.(subscribeFilter)
+				hosts = selectByMetadata(hosts, f.selector, f.fallback, anyInstance)
+				if len(hosts) == 0 {
+					(*funcItem)(subscribeServices, errors.New("[client.Subscribe] no host matches the selector "+f.selector.String()))
+					continue
+				}
+			}
+			for _, host := range hosts {
 				subscribeService := model.SubscribeService{
 					Valid:       host.Valid,
 					Port:        host.Port,
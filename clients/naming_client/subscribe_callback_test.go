@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/selector"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
@@ -174,3 +175,42 @@ func TestSubscribeCallback_ServiceChanged(t *testing.T) {
 
 	ed.ServiceChanged(&service)
 }
+
+func TestSubscribeCallback_ServiceChangedWithSelector(t *testing.T) {
+	service := model.Service{
+		Name:     "public@@Test",
+		Clusters: "default",
+		Hosts: []model.Instance{
+			{Ip: "10.0.0.1", Port: 80, Enable: true, Healthy: true, Metadata: map[string]string{"version": "v1"}},
+			{Ip: "10.0.0.2", Port: 80, Enable: true, Healthy: true, Metadata: map[string]string{"version": "v2"}},
+		},
+	}
+	ed := NewSubscribeCallback()
+	var selected, fallback []model.SubscribeService
+	var selectedErr error
+	selectedCallback := func(services []model.SubscribeService, err error) {
+		selected, selectedErr = services, err
+	}
+	fallbackCallback := func(services []model.SubscribeService, err error) {
+		fallback = services
+	}
+	ed.AddSelectorCallbackFuncs("public@@Test", "default", &selectedCallback, selector.MustParse("version=v2"), false)
+	ed.AddSelectorCallbackFuncs("public@@Test", "default", &fallbackCallback, selector.MustParse("version=v3"), true)
+
+	ed.ServiceChanged(&service)
+	assert.Nil(t, selectedErr)
+	assert.Equal(t, 1, len(selected))
+	assert.Equal(t, "10.0.0.2", selected[0].Ip)
+	assert.Equal(t, 2, len(fallback))
+
+	service.Hosts = service.Hosts[:1]
+	ed.ServiceChanged(&service)
+	assert.NotNil(t, selectedErr)
+	assert.Empty(t, selected)
+	assert.Equal(t, 1, len(fallback))
+
+	// the selector is dropped with the callback
+	ed.RemoveCallbackFuncs("public@@Test", "default", &selectedCallback)
+	_, ok := ed.filters.Load(&selectedCallback)
+	assert.False(t, ok)
+}
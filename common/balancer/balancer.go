@@ -0,0 +1,70 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package balancer
+
+import (
+	"math/rand"
+	"strconv"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+)
+
+// Request is the context of picking an instance
+type Request struct {
+	// Service is the cache key of the service, the stateful balancers keep their state per service
+	Service string
+	// Instances are the candidates, they are all healthy and enabled with a positive weight, the weight
+	// can be fractional, Instances is never empty
+	Instances []model.Instance
+	// HashKey is the key of the caller for the consistent hash balancer, such as the user id
+	HashKey string
+}
+
+// Balancer picks an instance for SelectOneHealthyInstance, it must be safe for concurrent use
+type Balancer interface {
+	Pick(req Request) model.Instance
+}
+
+type weightedRandom struct{}
+
+// NewWeightedRandom creates the default balancer, which picks an instance at random with the probability of its weight
+func NewWeightedRandom() Balancer {
+	return weightedRandom{}
+}
+
+func (weightedRandom) Pick(req Request) model.Instance {
+	total := 0.0
+	for _, instance := range req.Instances {
+		total += instance.Weight
+	}
+	if total <= 0 {
+		return req.Instances[rand.Intn(len(req.Instances))]
+	}
+	r := rand.Float64() * total
+	for _, instance := range req.Instances {
+		r -= instance.Weight
+		if r < 0 {
+			return instance
+		}
+	}
+	// the rounding error of the float
+	return req.Instances[len(req.Instances)-1]
+}
+
+func instanceKey(instance model.Instance) string {
+	return instance.Ip + ":" + strconv.FormatUint(instance.Port, 10)
+}
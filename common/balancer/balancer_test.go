@@ -0,0 +1,163 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package balancer
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+)
+
+func newInstances(weights ...float64) []model.Instance {
+	instances := make([]model.Instance, len(weights))
+	for i, weight := range weights {
+		instances[i] = model.Instance{Ip: "10.0.0." + strconv.Itoa(i+1), Port: 80, Weight: weight, Enable: true, Healthy: true}
+	}
+	return instances
+}
+
+func countPicks(b Balancer, req Request, n int) map[string]int {
+	picks := map[string]int{}
+	for i := 0; i < n; i++ {
+		picks[b.Pick(req).Ip]++
+	}
+	return picks
+}
+
+func TestWeightedRandom(t *testing.T) {
+	req := Request{Service: "DEFAULT_GROUP@@demo", Instances: newInstances(0.1, 0.3)}
+	picks := countPicks(NewWeightedRandom(), req, 10000)
+	assert.Equal(t, 10000, picks["10.0.0.1"]+picks["10.0.0.2"])
+	assert.InDelta(t, 2500, picks["10.0.0.1"], 300)
+}
+
+func TestSmoothWeightedRoundRobin(t *testing.T) {
+	b := NewSmoothWeightedRoundRobin()
+	req := Request{Service: "DEFAULT_GROUP@@demo", Instances: newInstances(5, 1, 1)}
+	var ips []string
+	for i := 0; i < 7; i++ {
+		ips = append(ips, b.Pick(req).Ip)
+	}
+	// the picks of the heavy instance are spread evenly
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.1", "10.0.0.2", "10.0.0.1", "10.0.0.3", "10.0.0.1", "10.0.0.1"}, ips)
+
+	req.Instances = newInstances(0.5, 0.25, 0.25)
+	assert.Equal(t, map[string]int{"10.0.0.1": 400, "10.0.0.2": 200, "10.0.0.3": 200}, countPicks(b, req, 800))
+}
+
+func TestLeastRecentlyPicked(t *testing.T) {
+	b := NewLeastRecentlyPicked()
+	req := Request{Service: "DEFAULT_GROUP@@demo", Instances: newInstances(1, 1, 1)}
+	assert.Equal(t, "10.0.0.1", b.Pick(req).Ip)
+	assert.Equal(t, "10.0.0.2", b.Pick(req).Ip)
+	// the new instance is picked first
+	req.Instances = append(req.Instances[1:], newInstances(1, 1, 1, 1)[3])
+	assert.Equal(t, "10.0.0.3", b.Pick(req).Ip)
+	assert.Equal(t, "10.0.0.4", b.Pick(req).Ip)
+	assert.Equal(t, "10.0.0.2", b.Pick(req).Ip)
+}
+
+func TestPowerOfTwoChoices(t *testing.T) {
+	b := NewPowerOfTwoChoices()
+	req := Request{Service: "DEFAULT_GROUP@@demo", Instances: newInstances(1)}
+	assert.Equal(t, "10.0.0.1", b.Pick(req).Ip)
+
+	req.Instances = newInstances(0.5, 0.5)
+	picks := countPicks(b, req, 1000)
+	// the instance picked fewer times is chosen when both are taken
+	assert.InDelta(t, 500, picks["10.0.0.1"], 50)
+
+	// the new instance starts from the least picked one, it doesn't take all the traffic
+	req.Instances = newInstances(0.5, 0.5, 0.5)
+	picks = countPicks(b, req, 300)
+	assert.InDelta(t, 100, picks["10.0.0.3"], 50)
+}
+
+func TestConsistentHash(t *testing.T) {
+	b := NewConsistentHash(0)
+	req := Request{Service: "DEFAULT_GROUP@@demo", Instances: newInstances(1, 1, 1, 1), HashKey: "user-1"}
+	first := b.Pick(req)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first, b.Pick(req))
+	}
+
+	// only the keys of the removed instance are moved
+	moved := 0
+	remained := make([]model.Instance, 0, 3)
+	for _, instance := range req.Instances {
+		if instance.Ip != "10.0.0.4" {
+			remained = append(remained, instance)
+		}
+	}
+	before := make([]string, 1000)
+	for i := range before {
+		before[i] = b.Pick(Request{Service: req.Service, Instances: req.Instances, HashKey: "user-" + strconv.Itoa(i)}).Ip
+	}
+	for i := range before {
+		after := b.Pick(Request{Service: req.Service, Instances: remained, HashKey: "user-" + strconv.Itoa(i)}).Ip
+		if before[i] != after {
+			assert.Equal(t, "10.0.0.4", before[i])
+			moved++
+		}
+	}
+	assert.True(t, moved > 0 && moved < 500)
+
+	// the ring is kept when the instances are reordered, and rebuilt when a weight changes
+	ring := b.(*consistentHash).getRing(req)
+	reordered := []model.Instance{req.Instances[3], req.Instances[2], req.Instances[1], req.Instances[0]}
+	assert.True(t, ring == b.(*consistentHash).getRing(Request{Service: req.Service, Instances: reordered}))
+	reordered[0].Weight = 2
+	assert.True(t, ring != b.(*consistentHash).getRing(Request{Service: req.Service, Instances: reordered}))
+
+	// the ring is rebuilt for the changed instances even if the checksum is the same
+	b.(*consistentHash).getRing(req)
+	replaced := append([]model.Instance{}, req.Instances...)
+	replaced[3].Ip = "10.0.0.5"
+	b.(*consistentHash).rings[req.Service].checksum = checksumInstances(replaced)
+	for i := 0; i < 100; i++ {
+		assert.NotEqual(t, "10.0.0.4", b.Pick(Request{Service: req.Service, Instances: replaced, HashKey: "user-" + strconv.Itoa(i)}).Ip)
+	}
+
+	// the fractional weights are in proportion
+	req = Request{Service: "DEFAULT_GROUP@@weighted", Instances: newInstances(0.1, 0.3)}
+	picks := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		req.HashKey = "user-" + strconv.Itoa(i)
+		picks[b.Pick(req).Ip]++
+	}
+	assert.InDelta(t, 1000, picks["10.0.0.1"], 300)
+}
+
+func TestBalancersConcurrently(t *testing.T) {
+	req := Request{Service: "DEFAULT_GROUP@@demo", Instances: newInstances(1, 2, 3), HashKey: "user-1"}
+	for _, b := range []Balancer{NewSmoothWeightedRoundRobin(), NewLeastRecentlyPicked(), NewPowerOfTwoChoices(), NewConsistentHash(10)} {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					b.Pick(req)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
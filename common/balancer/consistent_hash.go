@@ -0,0 +1,189 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package balancer
+
+import (
+	"hash/crc32"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+)
+
+const defaultReplicas = 100
+
+type hashRing struct {
+	// the checksum rejects the changed instances fast, the members in the order of the last request
+	// and the sorted fingerprint tell exactly whether the instances and their weights are the same
+	checksum    instancesChecksum
+	members     []ringMember
+	fingerprint string
+	hashes      []uint32
+	instances   []model.Instance
+}
+
+type ringMember struct {
+	ip     string
+	port   uint64
+	weight float64
+}
+
+// instancesChecksum doesn't depend on the order of the instances, which is not stable from server,
+// so it's computed in O(n) without sorting
+type instancesChecksum struct {
+	count int
+	sum   uint64
+	xor   uint64
+}
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+func checksumInstances(instances []model.Instance) instancesChecksum {
+	checksum := instancesChecksum{count: len(instances)}
+	for _, instance := range instances {
+		// FNV-1a of the ip, port and weight
+		h := uint64(fnvOffset64)
+		for i := 0; i < len(instance.Ip); i++ {
+			h = (h ^ uint64(instance.Ip[i])) * fnvPrime64
+		}
+		h = (h ^ instance.Port) * fnvPrime64
+		h = (h ^ math.Float64bits(instance.Weight)) * fnvPrime64
+		checksum.sum += h
+		checksum.xor ^= h
+	}
+	return checksum
+}
+
+type consistentHash struct {
+	replicas int
+	mutex    sync.Mutex
+	rings    map[string]*hashRing
+	fallback Balancer
+}
+
+// NewConsistentHash creates the balancer which picks the same instance for the same Request.HashKey
+// as long as the instance is available, the instance with the max weight has replicas virtual nodes on
+// the hash ring and the others have the nodes in proportion to their weights, default replicas is 100,
+// the request without HashKey is picked by the weighted random
+func NewConsistentHash(replicas int) Balancer {
+	if replicas <= 0 {
+		replicas = defaultReplicas
+	}
+	return &consistentHash{replicas: replicas, rings: map[string]*hashRing{}, fallback: NewWeightedRandom()}
+}
+
+func (b *consistentHash) Pick(req Request) model.Instance {
+	if len(req.HashKey) == 0 {
+		return b.fallback.Pick(req)
+	}
+	ring := b.getRing(req)
+	hash := crc32.ChecksumIEEE([]byte(req.HashKey))
+	i := sort.Search(len(ring.hashes), func(i int) bool {
+		return ring.hashes[i] >= hash
+	})
+	if i == len(ring.hashes) {
+		i = 0
+	}
+	return ring.instances[i]
+}
+
+func (b *consistentHash) getRing(req Request) *hashRing {
+	checksum := checksumInstances(req.Instances)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	ring, ok := b.rings[req.Service]
+	if ok && ring.checksum == checksum {
+		if ring.sameMembers(req.Instances) {
+			return ring
+		}
+		// the order of the instances is changed
+		if ring.fingerprint == fingerprintInstances(req.Instances) {
+			ring.members = membersOf(req.Instances)
+			return ring
+		}
+	}
+	ring = b.buildRing(req.Instances)
+	ring.checksum = checksum
+	ring.members = membersOf(req.Instances)
+	ring.fingerprint = fingerprintInstances(req.Instances)
+	b.rings[req.Service] = ring
+	return ring
+}
+
+func (ring *hashRing) sameMembers(instances []model.Instance) bool {
+	if len(ring.members) != len(instances) {
+		return false
+	}
+	for i, instance := range instances {
+		member := ring.members[i]
+		if member.ip != instance.Ip || member.port != instance.Port || member.weight != instance.Weight {
+			return false
+		}
+	}
+	return true
+}
+
+func membersOf(instances []model.Instance) []ringMember {
+	members := make([]ringMember, len(instances))
+	for i, instance := range instances {
+		members[i] = ringMember{ip: instance.Ip, port: instance.Port, weight: instance.Weight}
+	}
+	return members
+}
+
+func fingerprintInstances(instances []model.Instance) string {
+	keys := make([]string, len(instances))
+	for i, instance := range instances {
+		keys[i] = instanceKey(instance) + "@" + strconv.FormatFloat(instance.Weight, 'f', -1, 64)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+type ringNode struct {
+	hash     uint32
+	instance model.Instance
+}
+
+func (b *consistentHash) buildRing(instances []model.Instance) *hashRing {
+	maxWeight := 0.0
+	for _, instance := range instances {
+		maxWeight = math.Max(maxWeight, instance.Weight)
+	}
+	var nodes []ringNode
+	for _, instance := range instances {
+		replicas := int(math.Ceil(float64(b.replicas) * instance.Weight / maxWeight))
+		key := instanceKey(instance)
+		for i := 0; i < replicas; i++ {
+			nodes = append(nodes, ringNode{hash: crc32.ChecksumIEEE([]byte(key + "#" + strconv.Itoa(i))), instance: instance})
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].hash < nodes[j].hash
+	})
+	ring := &hashRing{hashes: make([]uint32, len(nodes)), instances: make([]model.Instance, len(nodes))}
+	for i, node := range nodes {
+		ring.hashes[i], ring.instances[i] = node.hash, node.instance
+	}
+	return ring
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package balancer
+
+import (
+	"sync"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+)
+
+type leastRecentlyPicked struct {
+	mutex sync.Mutex
+	seq   uint64
+	// the sequence of the last pick of the instances of each service, 0 means never picked
+	lastPicks map[string]map[string]uint64
+}
+
+// NewLeastRecentlyPicked creates the balancer which picks the instance not picked for the longest time,
+// the weights are ignored except that the new instances are picked first
+func NewLeastRecentlyPicked() Balancer {
+	return &leastRecentlyPicked{lastPicks: map[string]map[string]uint64{}}
+}
+
+func (b *leastRecentlyPicked) Pick(req Request) model.Instance {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	last := b.lastPicks[req.Service]
+	// the instances which are gone are dropped
+	next := make(map[string]uint64, len(req.Instances))
+	best, bestKey := 0, ""
+	for i, instance := range req.Instances {
+		key := instanceKey(instance)
+		next[key] = last[key]
+		if i == 0 || next[key] < next[bestKey] {
+			best, bestKey = i, key
+		}
+	}
+	b.seq++
+	next[bestKey] = b.seq
+	b.lastPicks[req.Service] = next
+	return req.Instances[best]
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package balancer
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+)
+
+type powerOfTwoChoices struct {
+	mutex sync.Mutex
+	// the count of the picks of the instances of each service
+	picks map[string]map[string]float64
+}
+
+// NewPowerOfTwoChoices creates the balancer which takes two instances at random and picks the one
+// picked fewer times relative to its weight, the new instance starts from the count of the least picked
+// instance, so it isn't flooded until it catches up with the others
+func NewPowerOfTwoChoices() Balancer {
+	return &powerOfTwoChoices{picks: map[string]map[string]float64{}}
+}
+
+func (b *powerOfTwoChoices) Pick(req Request) model.Instance {
+	n := len(req.Instances)
+	if n == 1 {
+		return req.Instances[0]
+	}
+	i := rand.Intn(n)
+	j := rand.Intn(n - 1)
+	if j >= i {
+		j++
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	picks, ok := b.picks[req.Service]
+	// the instances which are gone are dropped when there are too many of them
+	if !ok || len(picks) > 2*n {
+		picks = b.retain(picks, req.Instances)
+		b.picks[req.Service] = picks
+	}
+	keyI, keyJ := instanceKey(req.Instances[i]), instanceKey(req.Instances[j])
+	seedPicks(picks, req.Instances, keyI, req.Instances[i].Weight)
+	seedPicks(picks, req.Instances, keyJ, req.Instances[j].Weight)
+	if picks[keyJ]/req.Instances[j].Weight < picks[keyI]/req.Instances[i].Weight {
+		i, keyI = j, keyJ
+	}
+	picks[keyI]++
+	return req.Instances[i]
+}
+
+func (b *powerOfTwoChoices) retain(picks map[string]float64, instances []model.Instance) map[string]float64 {
+	retained := make(map[string]float64, len(instances))
+	for _, instance := range instances {
+		key := instanceKey(instance)
+		if count, ok := picks[key]; ok {
+			retained[key] = count
+		}
+	}
+	return retained
+}
+
+// seedPicks sets the count of the new instance to the min count of the known instances relative to the weight
+func seedPicks(picks map[string]float64, instances []model.Instance, key string, weight float64) {
+	if _, ok := picks[key]; ok {
+		return
+	}
+	minPicks, found := 0.0, false
+	for _, instance := range instances {
+		if count, ok := picks[instanceKey(instance)]; ok && (!found || count/instance.Weight < minPicks) {
+			minPicks, found = count/instance.Weight, true
+		}
+	}
+	picks[key] = minPicks * weight
+}
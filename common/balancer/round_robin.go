@@ -0,0 +1,58 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package balancer
+
+import (
+	"sync"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+)
+
+type smoothWeightedRoundRobin struct {
+	mutex sync.Mutex
+	// the current weights of the instances of each service
+	currentWeights map[string]map[string]float64
+}
+
+// NewSmoothWeightedRoundRobin creates the balancer of the smooth weighted round-robin as nginx,
+// the instances are picked in proportion to their weights and the picks of an instance are spread evenly
+func NewSmoothWeightedRoundRobin() Balancer {
+	return &smoothWeightedRoundRobin{currentWeights: map[string]map[string]float64{}}
+}
+
+// Every pick adds the weights to the current weights, picks the instance with the max current weight
+// and subtracts the total weight from it
+func (b *smoothWeightedRoundRobin) Pick(req Request) model.Instance {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	current := b.currentWeights[req.Service]
+	// the instances which are gone are dropped
+	next := make(map[string]float64, len(req.Instances))
+	total := 0.0
+	best, bestKey := 0, ""
+	for i, instance := range req.Instances {
+		key := instanceKey(instance)
+		next[key] = current[key] + instance.Weight
+		total += instance.Weight
+		if i == 0 || next[key] > next[bestKey] {
+			best, bestKey = i, key
+		}
+	}
+	next[bestKey] -= total
+	b.currentWeights[req.Service] = next
+	return req.Instances[best]
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_filter
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// AES_CIPHER_PREFIX is the prefix of the content encrypted by the aes filter,
+// the whole content is AES_CIPHER_PREFIX + keyVersion + ":" + base64(encrypted data key) + ":" + base64(encrypted content)
+const AES_CIPHER_PREFIX = "nacos-aes:"
+
+// KeyRing holds the versioned master keys, the primary key encrypts the new configs
+// and the other keys are kept to decrypt the configs encrypted before rotation
+type KeyRing struct {
+	primary string
+	keys    map[string][]byte
+}
+
+type keyRingJson struct {
+	Primary string            `json:"primary"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// ParseKeyRing parses the key ring in json format, the keys are base64 encoded AES-128, AES-192 or AES-256 keys
+//
+//	{"primary":"v2","keys":{"v1":"base64 key","v2":"base64 key"}}
+func ParseKeyRing(data []byte) (*KeyRing, error) {
+	var ringJson keyRingJson
+	if err := json.Unmarshal(data, &ringJson); err != nil {
+		return nil, fmt.Errorf("invalid key ring: %v", err)
+	}
+	if _, ok := ringJson.Keys[ringJson.Primary]; !ok {
+		return nil, fmt.Errorf("invalid key ring: primary key %q not found", ringJson.Primary)
+	}
+	ring := &KeyRing{primary: ringJson.Primary, keys: make(map[string][]byte, len(ringJson.Keys))}
+	for version, encoded := range ringJson.Keys {
+		if len(version) == 0 || strings.Contains(version, ":") {
+			return nil, fmt.Errorf("invalid key ring: invalid key version %q", version)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key ring: key %s is not base64 encoded", version)
+		}
+		if _, err = aes.NewCipher(key); err != nil {
+			return nil, fmt.Errorf("invalid key ring: key %s: %v", version, err)
+		}
+		ring.keys[version] = key
+	}
+	return ring, nil
+}
+
+// LoadKeyRingFromFile loads the key ring from the json file
+func LoadKeyRingFromFile(path string) (*KeyRing, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeyRing(data)
+}
+
+// LoadKeyRingFromEnv loads the key ring from the json value of the env var
+func LoadKeyRingFromEnv(name string) (*KeyRing, error) {
+	data, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("env %s not found", name)
+	}
+	return ParseKeyRing([]byte(data))
+}
+
+type aesFilter struct {
+	keyRing *KeyRing
+}
+
+// NewAESFilter returns the filter which encrypts the content of the dataIds start with "cipher-" locally,
+// each content is encrypted by a random data key with AES-GCM, and the data key is encrypted by the primary key of the key ring.
+// The group and dataId are authenticated with the content, so it can't be decrypted as another config.
+// The namespace is not authenticated, so the config exported by ExportConfigs can be imported into another namespace
+func NewAESFilter(keyRing *KeyRing) ConfigFilter {
+	return &aesFilter{keyRing: keyRing}
+}
+
+func (filter *aesFilter) Name() string {
+	return "aes"
+}
+
+func (filter *aesFilter) DoFilter(config *ConfigContext) error {
+	if !strings.HasPrefix(config.DataId, KMS_CIPHER_PREFIX) || len(config.Content) == 0 {
+		return nil
+	}
+	var err error
+	additionalData := configAdditionalData(config)
+	if config.Usage == USAGE_PUBLISH {
+		config.Content, err = filter.encrypt(additionalData, config.Content)
+	} else {
+		config.Content, err = filter.decrypt(additionalData, config.Content)
+	}
+	return err
+}
+
+// configAdditionalData encodes the group and dataId with their lengths, so the boundaries are not ambiguous
+func configAdditionalData(config *ConfigContext) []byte {
+	var data []byte
+	for _, part := range []string{config.Group, config.DataId} {
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(part)))
+		data = append(data, length[:]...)
+		data = append(data, part...)
+	}
+	return data
+}
+
+func (filter *aesFilter) encrypt(additionalData []byte, content string) (string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+	encryptedKey, err := sealAESGCM(filter.keyRing.keys[filter.keyRing.primary], dataKey, additionalData)
+	if err != nil {
+		return "", err
+	}
+	encryptedContent, err := sealAESGCM(dataKey, []byte(content), additionalData)
+	if err != nil {
+		return "", err
+	}
+	return AES_CIPHER_PREFIX + filter.keyRing.primary + ":" + base64.StdEncoding.EncodeToString(encryptedKey) +
+		":" + base64.StdEncoding.EncodeToString(encryptedContent), nil
+}
+
+func (filter *aesFilter) decrypt(additionalData []byte, content string) (string, error) {
+	if !strings.HasPrefix(content, AES_CIPHER_PREFIX) {
+		return "", errors.New("the content is not encrypted by the aes filter")
+	}
+	parts := strings.Split(strings.TrimPrefix(content, AES_CIPHER_PREFIX), ":")
+	if len(parts) != 3 {
+		return "", errors.New("invalid aes cipher content")
+	}
+	key, ok := filter.keyRing.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("key version %s not found in the key ring", parts[0])
+	}
+	encryptedKey, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("invalid aes cipher content")
+	}
+	encryptedContent, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("invalid aes cipher content")
+	}
+	dataKey, err := openAESGCM(key, encryptedKey, additionalData)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := openAESGCM(dataKey, encryptedContent, additionalData)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Encrypt by AES-GCM, the random nonce is put before the cipher text
+func sealAESGCM(key, plaintext, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func openAESGCM(key, ciphertext, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("invalid aes cipher content")
+	}
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], additionalData)
+}
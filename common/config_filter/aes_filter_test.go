@@ -0,0 +1,94 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_filter
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	keyV1 = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	keyV2 = "ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA="
+)
+
+func TestAESFilter(t *testing.T) {
+	ringV1, err := ParseKeyRing([]byte(`{"primary":"v1","keys":{"v1":"` + keyV1 + `"}}`))
+	assert.Nil(t, err)
+	filter := NewAESFilter(ringV1)
+
+	config := &ConfigContext{Usage: USAGE_PUBLISH, Namespace: "ns", Group: "group", DataId: "cipher-db", Content: "password=123"}
+	assert.Nil(t, filter.DoFilter(config))
+	assert.True(t, strings.HasPrefix(config.Content, AES_CIPHER_PREFIX+"v1:"))
+	encryptedV1 := config.Content
+
+	// the configs encrypted by the old key can be decrypted after rotation
+	ringV2, err := ParseKeyRing([]byte(`{"primary":"v2","keys":{"v1":"` + keyV1 + `","v2":"` + keyV2 + `"}}`))
+	assert.Nil(t, err)
+	filter = NewAESFilter(ringV2)
+	config = &ConfigContext{Usage: USAGE_GET, Namespace: "ns", Group: "group", DataId: "cipher-db", Content: encryptedV1}
+	assert.Nil(t, filter.DoFilter(config))
+	assert.Equal(t, "password=123", config.Content)
+
+	config = &ConfigContext{Usage: USAGE_PUBLISH, Namespace: "ns", Group: "group", DataId: "cipher-db", Content: "password=456"}
+	assert.Nil(t, filter.DoFilter(config))
+	assert.True(t, strings.HasPrefix(config.Content, AES_CIPHER_PREFIX+"v2:"))
+	encryptedV2 := config.Content
+	config = &ConfigContext{Usage: USAGE_NOTIFY, Namespace: "ns", Group: "group", DataId: "cipher-db", Content: encryptedV2}
+	assert.Nil(t, filter.DoFilter(config))
+	assert.Equal(t, "password=456", config.Content)
+
+	// the content is bound to the group and dataId
+	config = &ConfigContext{Usage: USAGE_GET, Namespace: "ns", Group: "group", DataId: "cipher-other", Content: encryptedV2}
+	assert.NotNil(t, filter.DoFilter(config))
+	config = &ConfigContext{Usage: USAGE_GET, Namespace: "ns", Group: "other", DataId: "cipher-db", Content: encryptedV2}
+	assert.NotNil(t, filter.DoFilter(config))
+
+	// but not to the namespace, "" and "public" are the same namespace, and the imported config is in another one
+	for _, namespace := range []string{"", "public", "other"} {
+		config = &ConfigContext{Usage: USAGE_GET, Namespace: namespace, Group: "group", DataId: "cipher-db", Content: encryptedV2}
+		assert.Nil(t, filter.DoFilter(config))
+		assert.Equal(t, "password=456", config.Content)
+	}
+
+	config = &ConfigContext{Usage: USAGE_GET, Namespace: "ns", Group: "group", DataId: "cipher-db", Content: encryptedV2}
+	assert.NotNil(t, NewAESFilter(ringV1).DoFilter(config))
+
+	config = &ConfigContext{Usage: USAGE_PUBLISH, DataId: "db", Content: "password=123"}
+	assert.Nil(t, filter.DoFilter(config))
+	assert.Equal(t, "password=123", config.Content)
+}
+
+func TestParseKeyRing(t *testing.T) {
+	_, err := ParseKeyRing([]byte(`{"primary":"v2","keys":{"v1":"` + keyV1 + `"}}`))
+	assert.NotNil(t, err)
+	_, err = ParseKeyRing([]byte(`{"primary":"v1","keys":{"v1":"YWJj"}}`))
+	assert.NotNil(t, err)
+	_, err = ParseKeyRing([]byte(`{"primary":"v:1","keys":{"v:1":"` + keyV1 + `"}}`))
+	assert.NotNil(t, err)
+
+	_ = os.Setenv("NACOS_TEST_KEY_RING", `{"primary":"v1","keys":{"v1":"`+keyV1+`"}}`)
+	defer os.Unsetenv("NACOS_TEST_KEY_RING")
+	ring, err := LoadKeyRingFromEnv("NACOS_TEST_KEY_RING")
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", ring.primary)
+	_, err = LoadKeyRingFromEnv("NACOS_TEST_KEY_RING_NOT_EXIST")
+	assert.NotNil(t, err)
+}
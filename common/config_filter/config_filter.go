@@ -0,0 +1,65 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_filter
+
+import (
+	"fmt"
+)
+
+type Usage string
+
+const (
+	USAGE_PUBLISH Usage = "publish" // the content is going to be published to server
+	USAGE_GET     Usage = "get"     // the content is got from server or cache by GetConfig
+	USAGE_NOTIFY  Usage = "notify"  // the content is going to be passed to the listeners
+)
+
+// ConfigContext is the config passed through the filters, filters can modify the content
+type ConfigContext struct {
+	Usage     Usage
+	Namespace string
+	Group     string
+	DataId    string
+	Content   string
+}
+
+// ConfigFilter processes the config content on publish, get and change notification
+type ConfigFilter interface {
+	// Name returns the name of the filter, it is used in the error message
+	Name() string
+	// DoFilter processes the config, an error aborts the publish, get or notification
+	DoFilter(config *ConfigContext) error
+}
+
+// DoFilters runs the filters in the registered order on publish and in the reverse order on get and notify,
+// so the filter registered first sees the content closest to the caller
+func DoFilters(filters []ConfigFilter, config *ConfigContext) error {
+	if config.Usage == USAGE_PUBLISH {
+		for _, filter := range filters {
+			if err := filter.DoFilter(config); err != nil {
+				return fmt.Errorf("[config_filter.%s] %s config failed: %v", filter.Name(), config.Usage, err)
+			}
+		}
+		return nil
+	}
+	for i := len(filters) - 1; i >= 0; i-- {
+		if err := filters[i].DoFilter(config); err != nil {
+			return fmt.Errorf("[config_filter.%s] %s config failed: %v", filters[i].Name(), config.Usage, err)
+		}
+	}
+	return nil
+}
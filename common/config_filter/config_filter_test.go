@@ -0,0 +1,64 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_filter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type appendFilter struct {
+	name string
+}
+
+func (filter *appendFilter) Name() string {
+	return filter.name
+}
+
+func (filter *appendFilter) DoFilter(config *ConfigContext) error {
+	if config.DataId == "error" {
+		return errors.New("filter error")
+	}
+	config.Content += "-" + filter.name
+	return nil
+}
+
+func TestDoFilters(t *testing.T) {
+	filters := []ConfigFilter{&appendFilter{name: "a"}, &appendFilter{name: "b"}}
+
+	config := &ConfigContext{Usage: USAGE_PUBLISH, Content: "content"}
+	assert.Nil(t, DoFilters(filters, config))
+	assert.Equal(t, "content-a-b", config.Content)
+
+	config = &ConfigContext{Usage: USAGE_GET, Content: "content"}
+	assert.Nil(t, DoFilters(filters, config))
+	assert.Equal(t, "content-b-a", config.Content)
+
+	config = &ConfigContext{Usage: USAGE_NOTIFY, DataId: "error"}
+	err := DoFilters(filters, config)
+	assert.Equal(t, "[config_filter.b] notify config failed: filter error", err.Error())
+}
+
+func TestKMSFilterSkipPlainDataId(t *testing.T) {
+	filter, err := NewKMSFilter("cn-shanghai", "accessKey", "secretKey")
+	assert.Nil(t, err)
+	config := &ConfigContext{Usage: USAGE_PUBLISH, DataId: "dataId", Content: "content"}
+	assert.Nil(t, filter.DoFilter(config))
+	assert.Equal(t, "content", config.Content)
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_filter
+
+import (
+	"strings"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/kms"
+)
+
+const KMS_CIPHER_PREFIX = "cipher-"
+
+type kmsFilter struct {
+	kmsClient *kms.Client
+}
+
+// NewKMSFilter returns the filter which encrypts and decrypts the content of the dataIds start with "cipher-" by aliyun kms
+func NewKMSFilter(regionId, accessKey, secretKey string) (ConfigFilter, error) {
+	kmsClient, err := kms.NewClientWithAccessKey(regionId, accessKey, secretKey)
+	if err != nil {
+		return nil, err
+	}
+	return &kmsFilter{kmsClient: kmsClient}, nil
+}
+
+func (filter *kmsFilter) Name() string {
+	return "kms"
+}
+
+func (filter *kmsFilter) DoFilter(config *ConfigContext) error {
+	if !strings.HasPrefix(config.DataId, KMS_CIPHER_PREFIX) || len(config.Content) == 0 {
+		return nil
+	}
+	if config.Usage == USAGE_PUBLISH {
+		request := kms.CreateEncryptRequest()
+		request.Method = "POST"
+		request.Scheme = "https"
+		request.AcceptFormat = "json"
+		request.KeyId = "alias/acs/acm" // use default key
+		request.Plaintext = config.Content
+		response, err := filter.kmsClient.Encrypt(request)
+		if err != nil {
+			return err
+		}
+		config.Content = response.CiphertextBlob
+		return nil
+	}
+	request := kms.CreateDecryptRequest()
+	request.Method = "POST"
+	request.Scheme = "https"
+	request.AcceptFormat = "json"
+	request.CiphertextBlob = config.Content
+	response, err := filter.kmsClient.Decrypt(request)
+	if err != nil {
+		return err
+	}
+	config.Content = response.Plaintext
+	return nil
+}
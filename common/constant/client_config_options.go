@@ -20,8 +20,11 @@ import (
 	"os"
 	"time"
 
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/balancer"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/config_filter"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/file"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
@@ -62,6 +65,20 @@ func WithTimeoutMs(timeoutMs uint64) ClientOption {
 	}
 }
 
+// WithListenBatchSize ...
+func WithListenBatchSize(listenBatchSize int) ClientOption {
+	return func(config *ClientConfig) {
+		config.ListenBatchSize = listenBatchSize
+	}
+}
+
+// WithListenMaxFailures ...
+func WithListenMaxFailures(listenMaxFailures int) ClientOption {
+	return func(config *ClientConfig) {
+		config.ListenMaxFailures = listenMaxFailures
+	}
+}
+
 // WithBeatInterval ...
 func WithBeatInterval(beatInterval int64) ClientOption {
 	return func(config *ClientConfig) {
@@ -111,6 +128,27 @@ func WithOpenKMS(openKMS bool) ClientOption {
 	}
 }
 
+// WithKeyRingFile ...
+func WithKeyRingFile(keyRingFile string) ClientOption {
+	return func(config *ClientConfig) {
+		config.KeyRingFile = keyRingFile
+	}
+}
+
+// WithKeyRingEnv ...
+func WithKeyRingEnv(keyRingEnv string) ClientOption {
+	return func(config *ClientConfig) {
+		config.KeyRingEnv = keyRingEnv
+	}
+}
+
+// WithConfigFilters ...
+func WithConfigFilters(filters ...config_filter.ConfigFilter) ClientOption {
+	return func(config *ClientConfig) {
+		config.ConfigFilters = append(config.ConfigFilters, filters...)
+	}
+}
+
 // WithCacheDir ...
 func WithCacheDir(cacheDir string) ClientOption {
 	return func(config *ClientConfig) {
@@ -118,6 +156,55 @@ func WithCacheDir(cacheDir string) ClientOption {
 	}
 }
 
+// WithCacheMaxAgeMs ...
+func WithCacheMaxAgeMs(cacheMaxAgeMs uint64) ClientOption {
+	return func(config *ClientConfig) {
+		config.CacheMaxAgeMs = cacheMaxAgeMs
+	}
+}
+
+// WithCacheStoreType ...
+func WithCacheStoreType(cacheStoreType CacheStoreType) ClientOption {
+	return func(config *ClientConfig) {
+		config.CacheStoreType = cacheStoreType
+	}
+}
+
+// WithConfigSnapshotDir ...
+func WithConfigSnapshotDir(snapshotDir string) ClientOption {
+	return func(config *ClientConfig) {
+		config.ConfigSnapshotDir = snapshotDir
+	}
+}
+
+// WithConfigFailoverDir ...
+func WithConfigFailoverDir(failoverDir string) ClientOption {
+	return func(config *ClientConfig) {
+		config.ConfigFailoverDir = failoverDir
+	}
+}
+
+// WithWarmUpConfigs ...
+func WithWarmUpConfigs(configs ...WarmUpConfig) ClientOption {
+	return func(config *ClientConfig) {
+		config.WarmUpConfigs = append(config.WarmUpConfigs, configs...)
+	}
+}
+
+// WithWarmUpServices ...
+func WithWarmUpServices(services ...WarmUpService) ClientOption {
+	return func(config *ClientConfig) {
+		config.WarmUpServices = append(config.WarmUpServices, services...)
+	}
+}
+
+// WithWarmUpTimeoutMs ...
+func WithWarmUpTimeoutMs(warmUpTimeoutMs uint64) ClientOption {
+	return func(config *ClientConfig) {
+		config.WarmUpTimeoutMs = warmUpTimeoutMs
+	}
+}
+
 // WithUpdateThreadNum ...
 func WithUpdateThreadNum(updateThreadNum int) ClientOption {
 	return func(config *ClientConfig) {
@@ -139,6 +226,62 @@ func WithUpdateCacheWhenEmpty(updateCacheWhenEmpty bool) ClientOption {
 	}
 }
 
+// WithDeregisterAtClose ...
+func WithDeregisterAtClose(deregisterAtClose bool) ClientOption {
+	return func(config *ClientConfig) {
+		config.DeregisterAtClose = deregisterAtClose
+	}
+}
+
+// WithBalancer ...
+func WithBalancer(balancer balancer.Balancer) ClientOption {
+	return func(config *ClientConfig) {
+		config.Balancer = balancer
+	}
+}
+
+// WithZone ...
+func WithZone(zone string) ClientOption {
+	return func(config *ClientConfig) {
+		config.Zone = zone
+	}
+}
+
+// WithZoneMetadataKey ...
+func WithZoneMetadataKey(zoneMetadataKey string) ClientOption {
+	return func(config *ClientConfig) {
+		config.ZoneMetadataKey = zoneMetadataKey
+	}
+}
+
+// WithZoneSpillThreshold ...
+func WithZoneSpillThreshold(zoneSpillThreshold float64) ClientOption {
+	return func(config *ClientConfig) {
+		config.ZoneSpillThreshold = zoneSpillThreshold
+	}
+}
+
+// WithUseProtectThreshold ...
+func WithUseProtectThreshold(useProtectThreshold bool) ClientOption {
+	return func(config *ClientConfig) {
+		config.UseProtectThreshold = useProtectThreshold
+	}
+}
+
+// WithOnProtectChange ...
+func WithOnProtectChange(onProtectChange model.ProtectListener) ClientOption {
+	return func(config *ClientConfig) {
+		config.OnProtectChange = onProtectChange
+	}
+}
+
+// WithOutlierDetection ...
+func WithOutlierDetection(outlierDetection OutlierDetection) ClientOption {
+	return func(config *ClientConfig) {
+		config.OutlierDetection = outlierDetection
+	}
+}
+
 // WithUsername ...
 func WithUsername(username string) ClientOption {
 	return func(config *ClientConfig) {
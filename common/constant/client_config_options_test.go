@@ -21,7 +21,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/balancer"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/file"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -58,40 +60,79 @@ func TestNewClientConfigWithOptions(t *testing.T) {
 		WithEndpoint("http://console.nacos.io:80"),
 		WithLogLevel("error"),
 		WithBeatInterval(int64(2000)),
+		WithListenBatchSize(1000),
+		WithListenMaxFailures(5),
 		WithUpdateThreadNum(30),
 
 		WithLogDir("/tmp/nacos/log"),
 		WithCacheDir("/tmp/nacos/cache"),
+		WithConfigSnapshotDir("/tmp/nacos/snapshot"),
+		WithCacheMaxAgeMs(uint64(86400000)),
+		WithCacheStoreType(CACHE_STORE_BOLT),
+		WithWarmUpConfigs(WarmUpConfig{DataId: "dataId", Group: "group"}),
+		WithWarmUpServices(WarmUpService{ServiceName: "demo", Clusters: []string{"a"}}),
+		WithWarmUpTimeoutMs(uint64(3000)),
+		WithConfigFailoverDir("/tmp/nacos/failover"),
 
 		WithNotLoadCacheAtStart(true),
 		WithUpdateCacheWhenEmpty(true),
+		WithDeregisterAtClose(true),
+		WithBalancer(balancer.NewSmoothWeightedRoundRobin()),
+		WithZone("zone-a"),
+		WithZoneMetadataKey("az"),
+		WithZoneSpillThreshold(0.5),
+		WithUseProtectThreshold(true),
+		WithOnProtectChange(func(state model.ProtectState) {}),
+		WithOutlierDetection(OutlierDetection{ConsecutiveFailures: 3, MaxEjectionPercent: 50}),
 
 		WithUsername("nacos"),
 		WithPassword("nacos"),
 		WithOpenKMS(true),
+		WithKeyRingFile("/tmp/nacos/keyring.json"),
+		WithKeyRingEnv("NACOS_KEY_RING"),
 		WithRegionId("shanghai"),
 		WithNamespaceId("namespace_1"),
 		WithAccessKey("accessKey_1"),
 		WithSecretKey("secretKey_1"),
 
 		WithLogSampling(time.Second*10, 5, 10),
+		WithConfigFilters(nil, nil),
 	)
 
 	assert.Equal(t, config.TimeoutMs, uint64(20000))
 	assert.Equal(t, config.Endpoint, "http://console.nacos.io:80")
 	assert.Equal(t, config.LogLevel, "error")
 	assert.Equal(t, config.BeatInterval, int64(2000))
+	assert.Equal(t, config.ListenBatchSize, 1000)
+	assert.Equal(t, config.ListenMaxFailures, 5)
 	assert.Equal(t, config.UpdateThreadNum, 30)
 
 	assert.Equal(t, config.LogDir, "/tmp/nacos/log")
 	assert.Equal(t, config.CacheDir, "/tmp/nacos/cache")
+	assert.Equal(t, config.ConfigSnapshotDir, "/tmp/nacos/snapshot")
+	assert.Equal(t, config.CacheMaxAgeMs, uint64(86400000))
+	assert.Equal(t, config.CacheStoreType, CACHE_STORE_BOLT)
+	assert.Equal(t, config.WarmUpConfigs, []WarmUpConfig{{DataId: "dataId", Group: "group"}})
+	assert.Equal(t, config.WarmUpServices, []WarmUpService{{ServiceName: "demo", Clusters: []string{"a"}}})
+	assert.Equal(t, config.WarmUpTimeoutMs, uint64(3000))
+	assert.Equal(t, config.ConfigFailoverDir, "/tmp/nacos/failover")
 
 	assert.Equal(t, config.NotLoadCacheAtStart, true)
 	assert.Equal(t, config.UpdateCacheWhenEmpty, true)
+	assert.Equal(t, config.DeregisterAtClose, true)
+	assert.NotNil(t, config.Balancer)
+	assert.Equal(t, config.Zone, "zone-a")
+	assert.Equal(t, config.ZoneMetadataKey, "az")
+	assert.Equal(t, config.ZoneSpillThreshold, 0.5)
+	assert.Equal(t, config.UseProtectThreshold, true)
+	assert.NotNil(t, config.OnProtectChange)
+	assert.Equal(t, config.OutlierDetection, OutlierDetection{ConsecutiveFailures: 3, MaxEjectionPercent: 50})
 
 	assert.Equal(t, config.Username, "nacos")
 	assert.Equal(t, config.Password, "nacos")
 	assert.Equal(t, config.OpenKMS, true)
+	assert.Equal(t, config.KeyRingFile, "/tmp/nacos/keyring.json")
+	assert.Equal(t, config.KeyRingEnv, "NACOS_KEY_RING")
 	assert.Equal(t, config.RegionId, "shanghai")
 	assert.Equal(t, config.NamespaceId, "namespace_1")
 	assert.Equal(t, config.AccessKey, "accessKey_1")
@@ -100,4 +141,6 @@ func TestNewClientConfigWithOptions(t *testing.T) {
 	assert.Equal(t, config.LogSampling.Tick, time.Second*10)
 	assert.Equal(t, config.LogSampling.Initial, 5)
 	assert.Equal(t, config.LogSampling.Thereafter, 10)
+
+	assert.Equal(t, len(config.ConfigFilters), 2)
 }
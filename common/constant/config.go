@@ -17,7 +17,10 @@
 package constant
 
 import (
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/balancer"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/config_filter"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
 
 	"gopkg.in/natefinch/lumberjack.v2"
 )
@@ -30,27 +33,75 @@ type ServerConfig struct {
 }
 
 type ClientConfig struct {
-	TimeoutMs            uint64                 // timeout for requesting Nacos server, default value is 10000ms
-	ListenInterval       uint64                 // Deprecated
-	BeatInterval         int64                  // the time interval for sending beat to server,default value is 5000ms
-	NamespaceId          string                 // the namespaceId of Nacos.When namespace is public, fill in the blank string here.
-	AppName              string                 // the appName
-	Endpoint             string                 // the endpoint for get Nacos server addresses
-	RegionId             string                 // the regionId for kms
-	AccessKey            string                 // the AccessKey for kms
-	SecretKey            string                 // the SecretKey for kms
-	OpenKMS              bool                   // it's to open kms,default is false. https://help.aliyun.com/product/28933.html
-	CacheDir             string                 // the directory for persist nacos service info,default value is current path
-	UpdateThreadNum      int                    // the number of gorutine for update nacos service info,default value is 20
-	NotLoadCacheAtStart  bool                   // not to load persistent nacos service info in CacheDir at start time
-	UpdateCacheWhenEmpty bool                   // update cache when get empty service instance from server
-	Username             string                 // the username for nacos auth
-	Password             string                 // the password for nacos auth
-	LogDir               string                 // the directory for log, default is current path
-	LogLevel             string                 // the level of log, it's must be debug,info,warn,error, default value is info
-	LogSampling          *logger.SamplingConfig // the sampling config of log
-	ContextPath          string                 // the nacos server contextpath
-	LogRollingConfig     *lumberjack.Logger     // the log rolling config
-	CustomLogger         logger.Logger          // the custom log interface ,With a custom Logger (nacos sdk will not provide log cutting and archiving capabilities)
-	AppendToStdout       bool                   // append log to stdout
+	TimeoutMs            uint64                       // timeout for requesting Nacos server, default value is 10000ms
+	ListenInterval       uint64                       // Deprecated
+	ListenBatchSize      int                          // the max count of the configs listened by a long polling request, default value is 3000
+	ListenMaxFailures    int                          // the listened configs are degraded after this count of consecutive long polling failures, default value is 3
+	BeatInterval         int64                        // the time interval for sending beat to server,default value is 5000ms
+	NamespaceId          string                       // the namespaceId of Nacos.When namespace is public, fill in the blank string here.
+	AppName              string                       // the appName
+	Endpoint             string                       // the endpoint for get Nacos server addresses
+	RegionId             string                       // the regionId for kms
+	AccessKey            string                       // the AccessKey for kms
+	SecretKey            string                       // the SecretKey for kms
+	OpenKMS              bool                         // it's to open kms,default is false. https://help.aliyun.com/product/28933.html
+	KeyRingFile          string                       // the json key ring file to encrypt the cipher- dataIds locally instead of kms
+	KeyRingEnv           string                       // the env var holding the json key ring to encrypt the cipher- dataIds locally instead of kms
+	CacheDir             string                       // the directory for persist nacos service info,default value is current path
+	ConfigSnapshotDir    string                       // the directory for the config snapshot which is only used when the server is unavailable, default is CacheDir/config
+	ConfigFailoverDir    string                       // the directory for the failover config files which take priority over the server, default is CacheDir/failover
+	CacheMaxAgeMs        uint64                       // the cache files not written for this long are removed at start time, default value is 0 which keeps them
+	CacheStoreType       CacheStoreType               // the storage of the config snapshot and the service cache, it's must be file,memory,bolt, default value is file
+	WarmUpConfigs        []WarmUpConfig               // the configs loaded in parallel into memory when the config client is created, see WaitReady
+	WarmUpServices       []WarmUpService              // the services loaded in parallel into the cache of the naming client when it's created, see WaitReady
+	WarmUpTimeoutMs      uint64                       // the max time for creating the client to wait for the warm-up, default value is TimeoutMs
+	UpdateThreadNum      int                          // the number of gorutine for update nacos service info,default value is 20
+	NotLoadCacheAtStart  bool                         // not to load persistent nacos service info in CacheDir at start time
+	UpdateCacheWhenEmpty bool                         // update cache when get empty service instance from server
+	DeregisterAtClose    bool                         // deregister the ephemeral instances registered by this client when close the naming client
+	Balancer             balancer.Balancer            // the balancer of SelectOneHealthyInstance, default is balancer.NewWeightedRandom()
+	Zone                 string                       // the zone of the client, SelectOneHealthyInstance prefers the instances in the same zone, default is empty which disables it
+	ZoneMetadataKey      string                       // the metadata key of the zone of the instances, default value is zone
+	ZoneSpillThreshold   float64                      // spill over to the other zones when the healthy weight ratio of Zone is below it, default value is 0 which spills over only when Zone has no healthy instance
+	UseProtectThreshold  bool                         // select the unhealthy instances too when the healthy ratio of a service is not above its protect threshold, which is refreshed from server in background every 30s
+	OnProtectChange      model.ProtectListener        // called when a service enters or leaves the protection mode of UseProtectThreshold, it should not block
+	OutlierDetection     OutlierDetection             // eject the instances failing in the results reported by ReportResult from the selection for a while
+	Username             string                       // the username for nacos auth
+	Password             string                       // the password for nacos auth
+	LogDir               string                       // the directory for log, default is current path
+	LogLevel             string                       // the level of log, it's must be debug,info,warn,error, default value is info
+	LogSampling          *logger.SamplingConfig       // the sampling config of log
+	ContextPath          string                       // the nacos server contextpath
+	LogRollingConfig     *lumberjack.Logger           // the log rolling config
+	CustomLogger         logger.Logger                // the custom log interface ,With a custom Logger (nacos sdk will not provide log cutting and archiving capabilities)
+	AppendToStdout       bool                         // append log to stdout
+	ConfigFilters        []config_filter.ConfigFilter // the filters of config content, run in order on publish and in reverse order on get and listen, the kms filter is the first one when OpenKMS
+}
+
+// WarmUpConfig is a config loaded from server or the snapshot when the config client is created,
+// it's kept in memory and updated by the long polling, so GetConfig of it does not request server
+type WarmUpConfig struct {
+	DataId string
+	Group  string
+}
+
+// OutlierDetection is the config of the outlier detection on the results reported by NamingClient.ReportResult,
+// an ejected instance is skipped by SelectOneHealthyInstance and SelectInstances with HealthyOnly until the ejection
+// time passes. The zero fields take the default values
+type OutlierDetection struct {
+	ConsecutiveFailures int     // eject an instance after this count of consecutive failures, default value is 5
+	ErrorRate           float64 // eject an instance when its error rate in IntervalMs reaches it, default value is 0.5
+	MinRequests         int     // the min count of the results in IntervalMs to check the error rate, default value is 10
+	IntervalMs          uint64  // the window of the error rate, default value is 10000ms
+	BaseEjectionTimeMs  uint64  // the n-th ejection of an instance lasts BaseEjectionTimeMs*2^(n-1), default value is 30000ms
+	MaxEjectionTimeMs   uint64  // the max time of an ejection, default value is 300000ms
+	MaxEjectionPercent  int     // the max percent of the instances of a service ejected at the same time, one instance can always be ejected, default value is 10
+}
+
+// WarmUpService is a service loaded from server or the cache when the naming client is created,
+// it's kept updated as the services got by SelectInstances
+type WarmUpService struct {
+	ServiceName string
+	GroupName   string   // default value is DEFAULT_GROUP
+	Clusters    []string // all the clusters are loaded when it is empty, the same as GetService and SelectInstances
 }
@@ -41,11 +41,14 @@ const (
 	KEY_TOKEN_TTL               = "tokenTtl"
 	KEY_GLOBAL_ADMIN            = "globalAdmin"
 	KEY_TOKEN_REFRESH_WINDOW    = "tokenRefreshWindow"
+	KEY_BETA_IPS                = "betaIps"
+	KEY_IS_BETA                 = "isBeta"
 	WEB_CONTEXT                 = "/nacos"
 	CONFIG_BASE_PATH            = "/v1/cs"
 	CONFIG_PATH                 = CONFIG_BASE_PATH + "/configs"
 	CONFIG_AGG_PATH             = "/datum.do"
 	CONFIG_LISTEN_PATH          = CONFIG_BASE_PATH + "/configs/listener"
+	CONFIG_HISTORY_PATH         = CONFIG_BASE_PATH + "/history"
 	SERVICE_BASE_PATH           = "/v1/ns"
 	SERVICE_PATH                = SERVICE_BASE_PATH + "/instance"
 	SERVICE_INFO_PATH           = SERVICE_BASE_PATH + "/service"
@@ -74,6 +77,7 @@ const (
 	CONFIG_INFO_SPLITER         = "@@"
 	DEFAULT_NAMESPACE_ID        = "public"
 	DEFAULT_GROUP               = "DEFAULT_GROUP"
+	DEFAULT_ZONE_METADATA_KEY   = "zone"
 	NAMING_INSTANCE_ID_SPLITTER = "#"
 	DefaultClientErrorCode      = "SDK.NacosError"
 	DEFAULT_SERVER_SCHEME       = "http"
@@ -81,3 +85,15 @@ const (
 	OS_WINDOWS                  = "windows"
 	LOG_FILE_NAME               = "nacos-sdk.log"
 )
+
+// CacheStoreType is the storage of the config snapshot and the service cache
+type CacheStoreType string
+
+const (
+	// CACHE_STORE_FILE stores a file for each config or service in the cache directory
+	CACHE_STORE_FILE CacheStoreType = "file"
+	// CACHE_STORE_MEMORY writes nothing to disk, the cache is lost when the process exits
+	CACHE_STORE_MEMORY CacheStoreType = "memory"
+	// CACHE_STORE_BOLT stores all the configs or services in a bbolt database file in the cache directory
+	CACHE_STORE_BOLT CacheStoreType = "bolt"
+)
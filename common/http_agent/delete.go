@@ -17,12 +17,13 @@
 package http_agent
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"time"
 )
 
-func delete(path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
+func delete(ctx context.Context, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
 	if !strings.HasSuffix(path, "?") {
 		path = path + "?"
 	}
@@ -34,7 +35,7 @@ func delete(path string, header http.Header, timeoutMs uint64, params map[string
 	}
 	client := http.Client{}
 	client.Timeout = time.Millisecond * time.Duration(timeoutMs)
-	request, errNew := http.NewRequest(http.MethodDelete, path, nil)
+	request, errNew := http.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
 	if errNew != nil {
 		err = errNew
 		return
@@ -17,12 +17,13 @@
 package http_agent
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"time"
 )
 
-func get(path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
+func get(ctx context.Context, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
 	if !strings.HasSuffix(path, "?") {
 		path = path + "?"
 	}
@@ -36,7 +37,7 @@ func get(path string, header http.Header, timeoutMs uint64, params map[string]st
 
 	client := http.Client{}
 	client.Timeout = time.Millisecond * time.Duration(timeoutMs)
-	request, errNew := http.NewRequest(http.MethodGet, path, nil)
+	request, errNew := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if errNew != nil {
 		err = errNew
 		return
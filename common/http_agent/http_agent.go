@@ -17,6 +17,7 @@
 package http_agent
 
 import (
+	"context"
 	"io/ioutil"
 	"net/http"
 
@@ -30,7 +31,7 @@ type HttpAgent struct {
 
 func (agent *HttpAgent) Get(path string, header http.Header, timeoutMs uint64,
 	params map[string]string) (response *http.Response, err error) {
-	return get(path, header, timeoutMs, params)
+	return get(context.Background(), path, header, timeoutMs, params)
 }
 
 func (agent *HttpAgent) RequestOnlyResult(method string, path string, header http.Header, timeoutMs uint64, params map[string]string) string {
@@ -71,18 +72,23 @@ func (agent *HttpAgent) RequestOnlyResult(method string, path string, header htt
 }
 
 func (agent *HttpAgent) Request(method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
+	return agent.RequestWithContext(context.Background(), method, path, header, timeoutMs, params)
+}
+
+// RequestWithContext is the same as Request, the in-flight request is aborted when ctx is done
+func (agent *HttpAgent) RequestWithContext(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
 	switch method {
 	case http.MethodGet:
-		response, err = agent.Get(path, header, timeoutMs, params)
+		response, err = get(ctx, path, header, timeoutMs, params)
 		return
 	case http.MethodPost:
-		response, err = agent.Post(path, header, timeoutMs, params)
+		response, err = post(ctx, path, header, timeoutMs, params)
 		return
 	case http.MethodPut:
-		response, err = agent.Put(path, header, timeoutMs, params)
+		response, err = put(ctx, path, header, timeoutMs, params)
 		return
 	case http.MethodDelete:
-		response, err = agent.Delete(path, header, timeoutMs, params)
+		response, err = delete(ctx, path, header, timeoutMs, params)
 		return
 	default:
 		err = errors.New("not available method")
@@ -90,15 +96,16 @@ func (agent *HttpAgent) Request(method string, path string, header http.Header,
 	}
 	return
 }
+
 func (agent *HttpAgent) Post(path string, header http.Header, timeoutMs uint64,
 	params map[string]string) (response *http.Response, err error) {
-	return post(path, header, timeoutMs, params)
+	return post(context.Background(), path, header, timeoutMs, params)
 }
 func (agent *HttpAgent) Delete(path string, header http.Header, timeoutMs uint64,
 	params map[string]string) (response *http.Response, err error) {
-	return delete(path, header, timeoutMs, params)
+	return delete(context.Background(), path, header, timeoutMs, params)
 }
 func (agent *HttpAgent) Put(path string, header http.Header, timeoutMs uint64,
 	params map[string]string) (response *http.Response, err error) {
-	return put(path, header, timeoutMs, params)
+	return put(context.Background(), path, header, timeoutMs, params)
 }
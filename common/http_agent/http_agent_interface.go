@@ -16,7 +16,10 @@
 
 package http_agent
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 //go:generate mockgen -destination ../../mock/mock_http_agent_interface.go -package mock -source=./http_agent_interface.go
 
@@ -27,4 +30,5 @@ type IHttpAgent interface {
 	Put(path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error)
 	RequestOnlyResult(method string, path string, header http.Header, timeoutMs uint64, params map[string]string) string
 	Request(method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error)
+	RequestWithContext(ctx context.Context, method string, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error)
 }
@@ -16,6 +16,7 @@
 package http_agent
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"time"
@@ -23,12 +24,12 @@ import (
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/util"
 )
 
-func post(path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
+func post(ctx context.Context, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
 	client := http.Client{}
 	client.Timeout = time.Millisecond * time.Duration(timeoutMs)
 
 	body := util.GetUrlFormedMap(params)
-	request, errNew := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	request, errNew := http.NewRequestWithContext(ctx, http.MethodPost, path, strings.NewReader(body))
 	if errNew != nil {
 		err = errNew
 		return
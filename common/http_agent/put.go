@@ -17,12 +17,13 @@
 package http_agent
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"time"
 )
 
-func put(path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
+func put(ctx context.Context, path string, header http.Header, timeoutMs uint64, params map[string]string) (response *http.Response, err error) {
 	client := http.Client{}
 	client.Timeout = time.Millisecond * time.Duration(timeoutMs)
 	var body string
@@ -34,7 +35,7 @@ func put(path string, header http.Header, timeoutMs uint64, params map[string]st
 	if strings.HasSuffix(body, "&") {
 		body = body[:len(body)-1]
 	}
-	request, errNew := http.NewRequest(http.MethodPut, path, strings.NewReader(body))
+	request, errNew := http.NewRequestWithContext(ctx, http.MethodPut, path, strings.NewReader(body))
 	if errNew != nil {
 		err = errNew
 		return
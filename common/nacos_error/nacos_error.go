@@ -52,3 +52,29 @@ func (err *NacosError) ErrorCode() string {
 		return err.errorCode
 	}
 }
+
+// ConfigNotFoundError is returned by UpdateConfigCAS when the config does not exist on server,
+// the compare and swap can not protect the creation of a config from the concurrent creators
+type ConfigNotFoundError struct {
+	DataId string
+	Group  string
+	Tenant string
+}
+
+func (err *ConfigNotFoundError) Error() string {
+	return fmt.Sprintf("config not found, dataId:%s group:%s tenant:%s does not exist on server",
+		err.DataId, err.Group, err.Tenant)
+}
+
+// ConfigConflictError is returned when the config on server is changed since the expected md5
+type ConfigConflictError struct {
+	DataId      string
+	Group       string
+	Tenant      string
+	ExpectedMd5 string
+}
+
+func (err *ConfigConflictError) Error() string {
+	return fmt.Sprintf("config conflict, the md5 of dataId:%s group:%s tenant:%s is not %s on server",
+		err.DataId, err.Group, err.Tenant, err.ExpectedMd5)
+}
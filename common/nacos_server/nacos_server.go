@@ -17,6 +17,7 @@
 package nacos_server
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha1"
 	"encoding/base64"
@@ -50,9 +51,11 @@ type NacosServer struct {
 	lastSrvRefTime      int64
 	vipSrvRefInterMills int64
 	contextPath         string
+	// the background goroutines which exit when the ctx of NewNacosServer is done
+	wg sync.WaitGroup
 }
 
-func NewNacosServer(serverList []constant.ServerConfig, clientCfg constant.ClientConfig, httpAgent http_agent.IHttpAgent, timeoutMs uint64, endpoint string) (*NacosServer, error) {
+func NewNacosServer(ctx context.Context, serverList []constant.ServerConfig, clientCfg constant.ClientConfig, httpAgent http_agent.IHttpAgent, timeoutMs uint64, endpoint string) (*NacosServer, error) {
 	if len(serverList) == 0 && endpoint == "" {
 		return &NacosServer{}, errors.New("both serverlist  and  endpoint are empty")
 	}
@@ -68,19 +71,24 @@ func NewNacosServer(serverList []constant.ServerConfig, clientCfg constant.Clien
 		vipSrvRefInterMills: 10000,
 		contextPath:         clientCfg.ContextPath,
 	}
-	ns.initRefreshSrvIfNeed()
+	ns.initRefreshSrvIfNeed(ctx)
 	_, err := securityLogin.Login()
 
 	if err != nil {
 		logger.Errorf("login has error %+v", err)
 	}
 
-	securityLogin.AutoRefresh()
+	securityLogin.AutoRefresh(ctx, &ns.wg)
 	return &ns, nil
 }
 
-func (server *NacosServer) callConfigServer(api string, params map[string]string, newHeaders map[string]string,
-	method string, curServer string, contextPath string, timeoutMS uint64) (result string, err error) {
+// Wait waits for the background goroutines to exit, the ctx of NewNacosServer must be done
+func (server *NacosServer) Wait() {
+	server.wg.Wait()
+}
+
+func (server *NacosServer) callConfigServer(ctx context.Context, api string, params map[string]string, newHeaders map[string]string,
+	method string, curServer string, contextPath string, timeoutMS uint64) (result string, responseHeader http.Header, err error) {
 	if contextPath == "" {
 		contextPath = constant.WEB_CONTEXT
 	}
@@ -113,7 +121,7 @@ func (server *NacosServer) callConfigServer(api string, params map[string]string
 	injectSecurityInfo(server, params)
 
 	var response *http.Response
-	response, err = server.httpAgent.Request(method, url, headers, timeoutMS, params)
+	response, err = server.httpAgent.RequestWithContext(ctx, method, url, headers, timeoutMS, params)
 	if err != nil {
 		return
 	}
@@ -124,6 +132,7 @@ func (server *NacosServer) callConfigServer(api string, params map[string]string
 		return
 	}
 	result = string(bytes)
+	responseHeader = response.Header
 	if response.StatusCode == 200 {
 		return
 	} else {
@@ -132,7 +141,7 @@ func (server *NacosServer) callConfigServer(api string, params map[string]string
 	}
 }
 
-func (server *NacosServer) callServer(api string, params map[string]string, header map[string]string, method string, curServer string, contextPath string) (result string, err error) {
+func (server *NacosServer) callServer(ctx context.Context, api string, params map[string]string, header map[string]string, method string, curServer string, contextPath string) (result string, err error) {
 	if contextPath == "" {
 		contextPath = constant.WEB_CONTEXT
 	}
@@ -160,7 +169,7 @@ func (server *NacosServer) callServer(api string, params map[string]string, head
 	injectSecurityInfo(server, params)
 
 	var response *http.Response
-	response, err = server.httpAgent.Request(method, url, headers, server.timeoutMs, params)
+	response, err = server.httpAgent.RequestWithContext(ctx, method, url, headers, server.timeoutMs, params)
 	if err != nil {
 		return
 	}
@@ -180,9 +189,21 @@ func (server *NacosServer) callServer(api string, params map[string]string, head
 }
 
 func (server *NacosServer) ReqConfigApi(api string, params map[string]string, headers map[string]string, method string, timeoutMS uint64) (string, error) {
+	return server.ReqConfigApiWithContext(context.Background(), api, params, headers, method, timeoutMS)
+}
+
+// ReqConfigApiWithContext is the same as ReqConfigApi, it stops retrying and aborts the in-flight request when ctx is done
+func (server *NacosServer) ReqConfigApiWithContext(ctx context.Context, api string, params map[string]string, headers map[string]string, method string, timeoutMS uint64) (string, error) {
+	result, _, err := server.ReqConfigApiWithResponseHeader(ctx, api, params, headers, method, timeoutMS)
+	return result, err
+}
+
+// ReqConfigApiWithResponseHeader is the same as ReqConfigApiWithContext, the headers of the successful response are returned too
+func (server *NacosServer) ReqConfigApiWithResponseHeader(ctx context.Context, api string, params map[string]string, headers map[string]string,
+	method string, timeoutMS uint64) (string, http.Header, error) {
 	srvs := server.serverList
 	if srvs == nil || len(srvs) == 0 {
-		return "", errors.New("server list is empty")
+		return "", nil, errors.New("server list is empty")
 	}
 
 	injectSecurityInfo(server, params)
@@ -190,31 +211,43 @@ func (server *NacosServer) ReqConfigApi(api string, params map[string]string, he
 	//only one server,retry request when error
 	var err error
 	var result string
+	var responseHeader http.Header
 	if len(srvs) == 1 {
 		for i := 0; i < constant.REQUEST_DOMAIN_RETRY_TIME; i++ {
-			result, err = server.callConfigServer(api, params, headers, method, getAddress(srvs[0]), srvs[0].ContextPath, timeoutMS)
+			if ctx.Err() != nil {
+				return "", nil, ctx.Err()
+			}
+			result, responseHeader, err = server.callConfigServer(ctx, api, params, headers, method, getAddress(srvs[0]), srvs[0].ContextPath, timeoutMS)
 			if err == nil {
-				return result, nil
+				return result, responseHeader, nil
 			}
 			logger.Errorf("api<%s>,method:<%s>, params:<%s>, call domain error:<%+v> , result:<%s>", api, method, util.ToJsonString(params), err, result)
 		}
-		return "", err
+		return "", nil, err
 	} else {
 		index := rand.Intn(len(srvs))
 		for i := 1; i <= len(srvs); i++ {
+			if ctx.Err() != nil {
+				return "", nil, ctx.Err()
+			}
 			curServer := srvs[index]
-			result, err = server.callConfigServer(api, params, headers, method, getAddress(curServer), curServer.ContextPath, timeoutMS)
+			result, responseHeader, err = server.callConfigServer(ctx, api, params, headers, method, getAddress(curServer), curServer.ContextPath, timeoutMS)
 			if err == nil {
-				return result, nil
+				return result, responseHeader, nil
 			}
 			logger.Errorf("[ERROR] api<%s>,method:<%s>, params:<%s>, call domain error:<%+v> , result:<%s> \n", api, method, util.ToJsonString(params), err, result)
 			index = (index + i) % len(srvs)
 		}
-		return "", err
+		return "", nil, err
 	}
 }
 
 func (server *NacosServer) ReqApi(api string, params map[string]string, method string, security map[string]string) (string, error) {
+	return server.ReqApiWithContext(context.Background(), api, params, method, security)
+}
+
+// ReqApiWithContext is the same as ReqApi, it stops retrying and aborts the in-flight request when ctx is done
+func (server *NacosServer) ReqApiWithContext(ctx context.Context, api string, params map[string]string, method string, security map[string]string) (string, error) {
 	srvs := server.serverList
 	if srvs == nil || len(srvs) == 0 {
 		return "", errors.New("server list is empty")
@@ -228,7 +261,10 @@ func (server *NacosServer) ReqApi(api string, params map[string]string, method s
 	//only one server,retry request when error
 	if len(srvs) == 1 {
 		for i := 0; i < constant.REQUEST_DOMAIN_RETRY_TIME; i++ {
-			result, err = server.callServer(api, params, signHeader, method, getAddress(srvs[0]), srvs[0].ContextPath)
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			result, err = server.callServer(ctx, api, params, signHeader, method, getAddress(srvs[0]), srvs[0].ContextPath)
 			if err == nil {
 				return result, nil
 			}
@@ -237,8 +273,11 @@ func (server *NacosServer) ReqApi(api string, params map[string]string, method s
 	} else {
 		index := rand.Intn(len(srvs))
 		for i := 1; i <= len(srvs); i++ {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
 			curServer := srvs[index]
-			result, err = server.callServer(api, params, signHeader, method, getAddress(curServer), curServer.ContextPath)
+			result, err = server.callServer(ctx, api, params, signHeader, method, getAddress(curServer), curServer.ContextPath)
 			if err == nil {
 				return result, nil
 			}
@@ -246,18 +285,29 @@ func (server *NacosServer) ReqApi(api string, params map[string]string, method s
 			index = (index + i) % len(srvs)
 		}
 	}
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
 	return "", fmt.Errorf("retry%stimes request failed,err=%v", strconv.Itoa(constant.REQUEST_DOMAIN_RETRY_TIME), err)
 }
 
-func (server *NacosServer) initRefreshSrvIfNeed() {
+func (server *NacosServer) initRefreshSrvIfNeed(ctx context.Context) {
 	if server.endpoint == "" {
 		return
 	}
 	server.refreshServerSrvIfNeed()
+	server.wg.Add(1)
 	go func() {
+		defer server.wg.Done()
+		ticker := time.NewTicker(time.Duration(1) * time.Second)
+		defer ticker.Stop()
 		for {
-			time.Sleep(time.Duration(1) * time.Second)
-			server.refreshServerSrvIfNeed()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				server.refreshServerSrvIfNeed()
+			}
 		}
 	}()
 
@@ -17,10 +17,13 @@
 package nacos_server
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/constant"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/http_agent"
 )
 
 func Test_getAddressWithScheme(t *testing.T) {
@@ -46,3 +49,27 @@ func Test_getAddressWithoutScheme(t *testing.T) {
 	assert.Equal(t, "https://console.nacos.io:80", getAddress(serverConfigTest))
 
 }
+
+func TestNacosServerWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// the server list is refreshed in background when the endpoint is set
+	server, err := NewNacosServer(ctx, []constant.ServerConfig{{IpAddr: "console.nacos.io", Port: 80}},
+		constant.ClientConfig{}, &http_agent.HttpAgent{}, 1000, "console.nacos.io:8080")
+	assert.Nil(t, err)
+	waited := make(chan struct{})
+	go func() {
+		server.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+		t.Fatal("the refreshing goroutine exits before ctx is done")
+	case <-time.After(50 * time.Millisecond):
+	}
+	cancel()
+	select {
+	case <-waited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the refreshing goroutine does not exit after ctx is done")
+	}
+}
@@ -17,12 +17,14 @@
 package security
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -65,7 +67,8 @@ func (ac *AuthClient) GetAccessToken() string {
 	return v.(string)
 }
 
-func (ac *AuthClient) AutoRefresh() {
+// AutoRefresh refresh the token periodically until ctx is done, the refreshing goroutine is added to wg
+func (ac *AuthClient) AutoRefresh(ctx context.Context, wg *sync.WaitGroup) {
 
 	// If the username is not set, the automatic refresh Token is not enabled
 
@@ -73,11 +76,16 @@ func (ac *AuthClient) AutoRefresh() {
 		return
 	}
 
+	wg.Add(1)
 	go func() {
+		defer wg.Done()
 		timer := time.NewTimer(time.Second * time.Duration(ac.tokenTtl-ac.tokenRefreshWindow))
 
 		for {
 			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
 			case <-timer.C:
 				_, err := ac.Login()
 				if err != nil {
@@ -0,0 +1,212 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+)
+
+type operator int
+
+const (
+	opEquals operator = iota
+	opNotEquals
+	opIn
+	opNotIn
+	opExists
+	opNotExists
+)
+
+const token = `[^\s!=(),]+`
+
+var (
+	setRequirement      = regexp.MustCompile(`^(` + token + `)\s+(in|notin)\s*\(([^()]*)\)$`)
+	equalityRequirement = regexp.MustCompile(`^(` + token + `)\s*(==|=|!=)\s*(` + token + `)?$`)
+	existsRequirement   = regexp.MustCompile(`^(!)?\s*(` + token + `)$`)
+)
+
+type requirement struct {
+	key      string
+	operator operator
+	values   map[string]struct{}
+}
+
+func (r requirement) matches(metadata map[string]string) bool {
+	value, ok := metadata[r.key]
+	switch r.operator {
+	case opExists:
+		return ok
+	case opNotExists:
+		return !ok
+	case opEquals, opIn:
+		if !ok {
+			return false
+		}
+		_, in := r.values[value]
+		return in
+	default:
+		// like the negative label selectors of kubernetes, an instance without the key matches != and notin
+		if !ok {
+			return true
+		}
+		_, in := r.values[value]
+		return !in
+	}
+}
+
+// Selector is a parsed metadata selector, the instances whose metadata match all of its requirements are selected.
+// A Selector is immutable and safe for concurrent use
+type Selector struct {
+	expr         string
+	requirements []requirement
+}
+
+// Parse parses a selector expression, which is a comma separated list of requirements on the metadata:
+//
+//	key=value, key==value  the metadata has the key with the value
+//	key!=value             the metadata does not have the key with the value
+//	key in (v1,v2)         the metadata has the key with one of the values
+//	key notin (v1,v2)      the metadata does not have the key with any of the values
+//	key                    the metadata has the key
+//	!key                   the metadata does not have the key
+//
+// such as "version=v2,zone in (a,b),!canary". The empty expression selects everything
+func Parse(expr string) (*Selector, error) {
+	s := &Selector{expr: strings.TrimSpace(expr)}
+	if s.expr == "" {
+		return s, nil
+	}
+	terms, err := splitTerms(s.expr)
+	if err != nil {
+		return nil, err
+	}
+	for _, term := range terms {
+		r, err := parseRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		s.requirements = append(s.requirements, r)
+	}
+	return s, nil
+}
+
+// MustParse is like Parse but panics when the expression is invalid
+func MustParse(expr string) *Selector {
+	s, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// splitTerms splits the expression by the commas outside the parentheses
+func splitTerms(expr string) ([]string, error) {
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range expr {
+		switch c {
+		case '(':
+			depth++
+			if depth > 1 {
+				return nil, fmt.Errorf("invalid selector %q: nested parentheses", expr)
+			}
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("invalid selector %q: unbalanced parentheses", expr)
+			}
+		case ',':
+			if depth == 0 {
+				terms = append(terms, strings.TrimSpace(expr[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid selector %q: unbalanced parentheses", expr)
+	}
+	return append(terms, strings.TrimSpace(expr[start:])), nil
+}
+
+func parseRequirement(term string) (requirement, error) {
+	if m := setRequirement.FindStringSubmatch(term); m != nil {
+		r := requirement{key: m[1], operator: opIn, values: map[string]struct{}{}}
+		if m[2] == "notin" {
+			r.operator = opNotIn
+		}
+		for _, value := range strings.Split(m[3], ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				return requirement{}, fmt.Errorf("invalid selector requirement %q: empty value in the set", term)
+			}
+			r.values[value] = struct{}{}
+		}
+		return r, nil
+	}
+	if m := equalityRequirement.FindStringSubmatch(term); m != nil {
+		r := requirement{key: m[1], operator: opEquals, values: map[string]struct{}{m[3]: {}}}
+		if m[2] == "!=" {
+			r.operator = opNotEquals
+		}
+		return r, nil
+	}
+	if m := existsRequirement.FindStringSubmatch(term); m != nil {
+		r := requirement{key: m[2], operator: opExists}
+		if m[1] == "!" {
+			r.operator = opNotExists
+		}
+		return r, nil
+	}
+	return requirement{}, fmt.Errorf("invalid selector requirement %q", term)
+}
+
+// Empty reports whether the selector selects everything, a nil selector is empty
+func (s *Selector) Empty() bool {
+	return s == nil || len(s.requirements) == 0
+}
+
+// Matches reports whether the metadata match all the requirements
+func (s *Selector) Matches(metadata map[string]string) bool {
+	for _, r := range s.requirements {
+		if !r.matches(metadata) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the instances whose metadata match the selector, the input slice is not modified
+func (s *Selector) Filter(instances []model.Instance) []model.Instance {
+	if s.Empty() {
+		return instances
+	}
+	var result []model.Instance
+	for _, instance := range instances {
+		if s.Matches(instance.Metadata) {
+			result = append(result, instance)
+		}
+	}
+	return result
+}
+
+func (s *Selector) String() string {
+	return s.expr
+}
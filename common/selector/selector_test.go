@@ -0,0 +1,78 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+)
+
+func TestSelectorMatches(t *testing.T) {
+	metadata := map[string]string{"version": "v2", "zone": "a", "canary": "true"}
+	cases := []struct {
+		expr    string
+		matches bool
+	}{
+		{"", true},
+		{"version=v2", true},
+		{"version==v2", true},
+		{"version = v1", false},
+		{"version!=v1", true},
+		{"version!=v2", false},
+		{"region!=cn", true},
+		{"zone in (a, b)", true},
+		{"zone in (b,c)", false},
+		{"region in (a)", false},
+		{"zone notin (b,c)", true},
+		{"zone notin (a)", false},
+		{"region notin (a)", true},
+		{"canary", true},
+		{"!canary", false},
+		{"! region", true},
+		{"version=v2, zone in (a,b), !region", true},
+		{"version=v2,zone in (b,c)", false},
+	}
+	for _, c := range cases {
+		s, err := Parse(c.expr)
+		assert.Nil(t, err, c.expr)
+		assert.Equal(t, c.matches, s.Matches(metadata), c.expr)
+	}
+}
+
+func TestParseInvalidSelector(t *testing.T) {
+	for _, expr := range []string{"version=v2,", "zone in (a,", "zone in (a,,b)", "zone in ((a))", "zone in a", "version=v2=v3", "a b"} {
+		_, err := Parse(expr)
+		assert.NotNil(t, err, expr)
+	}
+	assert.Panics(t, func() { MustParse("!") })
+}
+
+func TestSelectorFilter(t *testing.T) {
+	instances := []model.Instance{
+		{InstanceId: "1", Metadata: map[string]string{"version": "v1"}},
+		{InstanceId: "2", Metadata: map[string]string{"version": "v2"}},
+		{InstanceId: "3"},
+	}
+	filtered := MustParse("version in (v2,v3)").Filter(instances)
+	assert.Equal(t, 1, len(filtered))
+	assert.Equal(t, "2", filtered[0].InstanceId)
+	assert.Equal(t, 2, len(MustParse("version!=v2").Filter(instances)))
+	assert.Equal(t, 3, len(MustParse(" ").Filter(instances)))
+	assert.Equal(t, "version=v1", MustParse(" version=v1 ").String())
+}
@@ -97,7 +97,7 @@ func main() {
 	fmt.Println("GetConfig,config :" + content)
 
 	//Listen config change,key=dataId+group+namespaceId.
-	err = client.ListenConfig(vo.ConfigParam{
+	_, err = client.ListenConfig(vo.ConfigParam{
 		DataId: "test-data",
 		Group:  "test-group",
 		OnChange: func(namespace, group, dataId, data string) {
@@ -105,7 +105,7 @@ func main() {
 		},
 	})
 
-	err = client.ListenConfig(vo.ConfigParam{
+	handle, err := client.ListenConfig(vo.ConfigParam{
 		DataId: "test-data-2",
 		Group:  "test-group",
 		OnChange: func(namespace, group, dataId, data string) {
@@ -135,6 +135,9 @@ func main() {
 		Group:  "test-group",
 	})
 
+	//cancel a single listener by the handle returned from ListenConfig
+	err = client.CancelListener(handle)
+
 	time.Sleep(2 * time.Second)
 	_, err = client.DeleteConfig(vo.ConfigParam{
 		DataId: "test-data",
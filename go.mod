@@ -10,8 +10,10 @@ require (
 	github.com/json-iterator/go v1.1.6 // indirect
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.5.1
+	go.etcd.io/bbolt v1.3.6
 	go.uber.org/zap v1.15.0
 	golang.org/x/sync v0.0.0-20190423024810-112230192c58
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0
+	gopkg.in/yaml.v2 v2.2.2
 )
@@ -21,9 +21,12 @@
 package mock
 
 import (
+	context "context"
+	io "io"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	model "github.com/yefengzhichen/nacos-sdk-go-v1x/model"
 	vo "github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
 )
 
@@ -65,6 +68,83 @@ func (mr *MockIConfigClientMockRecorder) GetConfig(param interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockIConfigClient)(nil).GetConfig), param)
 }
 
+// GetConfigWithContext mocks base method
+func (m *MockIConfigClient) GetConfigWithContext(ctx context.Context, param vo.ConfigParam) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigWithContext", ctx, param)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigWithContext indicates an expected call of GetConfigWithContext
+func (mr *MockIConfigClientMockRecorder) GetConfigWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigWithContext", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigWithContext), ctx, param)
+}
+
+// GetConfigWithSource mocks base method
+func (m *MockIConfigClient) GetConfigWithSource(param vo.ConfigParam) (string, vo.ConfigSource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigWithSource", param)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(vo.ConfigSource)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetConfigWithSource indicates an expected call of GetConfigWithSource
+func (mr *MockIConfigClientMockRecorder) GetConfigWithSource(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigWithSource", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigWithSource), param)
+}
+
+// GetConfigWithSourceWithContext mocks base method
+func (m *MockIConfigClient) GetConfigWithSourceWithContext(ctx context.Context, param vo.ConfigParam) (string, vo.ConfigSource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigWithSourceWithContext", ctx, param)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(vo.ConfigSource)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetConfigWithSourceWithContext indicates an expected call of GetConfigWithSourceWithContext
+func (mr *MockIConfigClientMockRecorder) GetConfigWithSourceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigWithSourceWithContext", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigWithSourceWithContext), ctx, param)
+}
+
+// GetConfigDetail mocks base method
+func (m *MockIConfigClient) GetConfigDetail(param vo.ConfigParam) (*model.ConfigDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigDetail", param)
+	ret0, _ := ret[0].(*model.ConfigDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigDetail indicates an expected call of GetConfigDetail
+func (mr *MockIConfigClientMockRecorder) GetConfigDetail(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigDetail", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigDetail), param)
+}
+
+// GetConfigDetailWithContext mocks base method
+func (m *MockIConfigClient) GetConfigDetailWithContext(ctx context.Context, param vo.ConfigParam) (*model.ConfigDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigDetailWithContext", ctx, param)
+	ret0, _ := ret[0].(*model.ConfigDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigDetailWithContext indicates an expected call of GetConfigDetailWithContext
+func (mr *MockIConfigClientMockRecorder) GetConfigDetailWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigDetailWithContext", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigDetailWithContext), ctx, param)
+}
+
 // PublishConfig mocks base method
 func (m *MockIConfigClient) PublishConfig(param vo.ConfigParam) (bool, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +160,259 @@ func (mr *MockIConfigClientMockRecorder) PublishConfig(param interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishConfig", reflect.TypeOf((*MockIConfigClient)(nil).PublishConfig), param)
 }
 
+// PublishConfigWithContext mocks base method
+func (m *MockIConfigClient) PublishConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishConfigWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishConfigWithContext indicates an expected call of PublishConfigWithContext
+func (mr *MockIConfigClientMockRecorder) PublishConfigWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishConfigWithContext", reflect.TypeOf((*MockIConfigClient)(nil).PublishConfigWithContext), ctx, param)
+}
+
+// PublishConfigCAS mocks base method
+func (m *MockIConfigClient) PublishConfigCAS(param vo.ConfigParam, expectedMd5 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishConfigCAS", param, expectedMd5)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishConfigCAS indicates an expected call of PublishConfigCAS
+func (mr *MockIConfigClientMockRecorder) PublishConfigCAS(param, expectedMd5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishConfigCAS", reflect.TypeOf((*MockIConfigClient)(nil).PublishConfigCAS), param, expectedMd5)
+}
+
+// PublishConfigCASWithContext mocks base method
+func (m *MockIConfigClient) PublishConfigCASWithContext(ctx context.Context, param vo.ConfigParam, expectedMd5 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishConfigCASWithContext", ctx, param, expectedMd5)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishConfigCASWithContext indicates an expected call of PublishConfigCASWithContext
+func (mr *MockIConfigClientMockRecorder) PublishConfigCASWithContext(ctx, param, expectedMd5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishConfigCASWithContext", reflect.TypeOf((*MockIConfigClient)(nil).PublishConfigCASWithContext), ctx, param, expectedMd5)
+}
+
+// UpdateConfigCAS mocks base method
+func (m *MockIConfigClient) UpdateConfigCAS(param vo.ConfigParam, maxRetries int, modify func(string) (string, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfigCAS", param, maxRetries, modify)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConfigCAS indicates an expected call of UpdateConfigCAS
+func (mr *MockIConfigClientMockRecorder) UpdateConfigCAS(param, maxRetries, modify interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigCAS", reflect.TypeOf((*MockIConfigClient)(nil).UpdateConfigCAS), param, maxRetries, modify)
+}
+
+// UpdateConfigCASWithContext mocks base method
+func (m *MockIConfigClient) UpdateConfigCASWithContext(ctx context.Context, param vo.ConfigParam, maxRetries int, modify func(string) (string, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfigCASWithContext", ctx, param, maxRetries, modify)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConfigCASWithContext indicates an expected call of UpdateConfigCASWithContext
+func (mr *MockIConfigClientMockRecorder) UpdateConfigCASWithContext(ctx, param, maxRetries, modify interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigCASWithContext", reflect.TypeOf((*MockIConfigClient)(nil).UpdateConfigCASWithContext), ctx, param, maxRetries, modify)
+}
+
+// ListConfigHistory mocks base method
+func (m *MockIConfigClient) ListConfigHistory(param vo.ConfigParam, page vo.PageParam) (*model.ConfigHistoryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConfigHistory", param, page)
+	ret0, _ := ret[0].(*model.ConfigHistoryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConfigHistory indicates an expected call of ListConfigHistory
+func (mr *MockIConfigClientMockRecorder) ListConfigHistory(param, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfigHistory", reflect.TypeOf((*MockIConfigClient)(nil).ListConfigHistory), param, page)
+}
+
+// ListConfigHistoryWithContext mocks base method
+func (m *MockIConfigClient) ListConfigHistoryWithContext(ctx context.Context, param vo.ConfigParam, page vo.PageParam) (*model.ConfigHistoryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConfigHistoryWithContext", ctx, param, page)
+	ret0, _ := ret[0].(*model.ConfigHistoryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConfigHistoryWithContext indicates an expected call of ListConfigHistoryWithContext
+func (mr *MockIConfigClientMockRecorder) ListConfigHistoryWithContext(ctx, param, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfigHistoryWithContext", reflect.TypeOf((*MockIConfigClient)(nil).ListConfigHistoryWithContext), ctx, param, page)
+}
+
+// GetConfigHistory mocks base method
+func (m *MockIConfigClient) GetConfigHistory(nid int64) (*model.ConfigHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigHistory", nid)
+	ret0, _ := ret[0].(*model.ConfigHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigHistory indicates an expected call of GetConfigHistory
+func (mr *MockIConfigClientMockRecorder) GetConfigHistory(nid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigHistory", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigHistory), nid)
+}
+
+// GetConfigHistoryWithContext mocks base method
+func (m *MockIConfigClient) GetConfigHistoryWithContext(ctx context.Context, nid int64) (*model.ConfigHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigHistoryWithContext", ctx, nid)
+	ret0, _ := ret[0].(*model.ConfigHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigHistoryWithContext indicates an expected call of GetConfigHistoryWithContext
+func (mr *MockIConfigClientMockRecorder) GetConfigHistoryWithContext(ctx, nid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigHistoryWithContext", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigHistoryWithContext), ctx, nid)
+}
+
+// RollbackConfig mocks base method
+func (m *MockIConfigClient) RollbackConfig(param vo.ConfigParam, nid int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackConfig", param, nid)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackConfig indicates an expected call of RollbackConfig
+func (mr *MockIConfigClientMockRecorder) RollbackConfig(param, nid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackConfig", reflect.TypeOf((*MockIConfigClient)(nil).RollbackConfig), param, nid)
+}
+
+// RollbackConfigWithContext mocks base method
+func (m *MockIConfigClient) RollbackConfigWithContext(ctx context.Context, param vo.ConfigParam, nid int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackConfigWithContext", ctx, param, nid)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackConfigWithContext indicates an expected call of RollbackConfigWithContext
+func (mr *MockIConfigClientMockRecorder) RollbackConfigWithContext(ctx, param, nid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackConfigWithContext", reflect.TypeOf((*MockIConfigClient)(nil).RollbackConfigWithContext), ctx, param, nid)
+}
+
+// PublishConfigBeta mocks base method
+func (m *MockIConfigClient) PublishConfigBeta(param vo.ConfigParam, betaIps []string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishConfigBeta", param, betaIps)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishConfigBeta indicates an expected call of PublishConfigBeta
+func (mr *MockIConfigClientMockRecorder) PublishConfigBeta(param, betaIps interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishConfigBeta", reflect.TypeOf((*MockIConfigClient)(nil).PublishConfigBeta), param, betaIps)
+}
+
+// PublishConfigBetaWithContext mocks base method
+func (m *MockIConfigClient) PublishConfigBetaWithContext(ctx context.Context, param vo.ConfigParam, betaIps []string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishConfigBetaWithContext", ctx, param, betaIps)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishConfigBetaWithContext indicates an expected call of PublishConfigBetaWithContext
+func (mr *MockIConfigClientMockRecorder) PublishConfigBetaWithContext(ctx, param, betaIps interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishConfigBetaWithContext", reflect.TypeOf((*MockIConfigClient)(nil).PublishConfigBetaWithContext), ctx, param, betaIps)
+}
+
+// GetConfigBeta mocks base method
+func (m *MockIConfigClient) GetConfigBeta(param vo.ConfigParam) (*model.ConfigBeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigBeta", param)
+	ret0, _ := ret[0].(*model.ConfigBeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigBeta indicates an expected call of GetConfigBeta
+func (mr *MockIConfigClientMockRecorder) GetConfigBeta(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigBeta", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigBeta), param)
+}
+
+// GetConfigBetaWithContext mocks base method
+func (m *MockIConfigClient) GetConfigBetaWithContext(ctx context.Context, param vo.ConfigParam) (*model.ConfigBeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigBetaWithContext", ctx, param)
+	ret0, _ := ret[0].(*model.ConfigBeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigBetaWithContext indicates an expected call of GetConfigBetaWithContext
+func (mr *MockIConfigClientMockRecorder) GetConfigBetaWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigBetaWithContext", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigBetaWithContext), ctx, param)
+}
+
+// StopBeta mocks base method
+func (m *MockIConfigClient) StopBeta(param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopBeta", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopBeta indicates an expected call of StopBeta
+func (mr *MockIConfigClientMockRecorder) StopBeta(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopBeta", reflect.TypeOf((*MockIConfigClient)(nil).StopBeta), param)
+}
+
+// StopBetaWithContext mocks base method
+func (m *MockIConfigClient) StopBetaWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopBetaWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopBetaWithContext indicates an expected call of StopBetaWithContext
+func (mr *MockIConfigClientMockRecorder) StopBetaWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopBetaWithContext", reflect.TypeOf((*MockIConfigClient)(nil).StopBetaWithContext), ctx, param)
+}
+
 // DeleteConfig mocks base method
 func (m *MockIConfigClient) DeleteConfig(param vo.ConfigParam) (bool, error) {
 	m.ctrl.T.Helper()
@@ -95,12 +428,28 @@ func (mr *MockIConfigClientMockRecorder) DeleteConfig(param interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfig", reflect.TypeOf((*MockIConfigClient)(nil).DeleteConfig), param)
 }
 
+// DeleteConfigWithContext mocks base method
+func (m *MockIConfigClient) DeleteConfigWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfigWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteConfigWithContext indicates an expected call of DeleteConfigWithContext
+func (mr *MockIConfigClientMockRecorder) DeleteConfigWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigWithContext", reflect.TypeOf((*MockIConfigClient)(nil).DeleteConfigWithContext), ctx, param)
+}
+
 // ListenConfig mocks base method
-func (m *MockIConfigClient) ListenConfig(params []vo.ConfigParam) error {
+func (m *MockIConfigClient) ListenConfig(params vo.ConfigParam) (vo.ListenerHandle, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListenConfig", params)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(vo.ListenerHandle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ListenConfig indicates an expected call of ListenConfig
@@ -109,43 +458,322 @@ func (mr *MockIConfigClientMockRecorder) ListenConfig(params interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenConfig", reflect.TypeOf((*MockIConfigClient)(nil).ListenConfig), params)
 }
 
-// AddConfigToListen mocks base method
-func (m *MockIConfigClient) AddConfigToListen(params []vo.ConfigParam) error {
+// ListenConfigWithContext mocks base method
+func (m *MockIConfigClient) ListenConfigWithContext(ctx context.Context, params vo.ConfigParam) (vo.ListenerHandle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenConfigWithContext", ctx, params)
+	ret0, _ := ret[0].(vo.ListenerHandle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListenConfigWithContext indicates an expected call of ListenConfigWithContext
+func (mr *MockIConfigClientMockRecorder) ListenConfigWithContext(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenConfigWithContext", reflect.TypeOf((*MockIConfigClient)(nil).ListenConfigWithContext), ctx, params)
+}
+
+// CancelListenConfig mocks base method
+func (m *MockIConfigClient) CancelListenConfig(params vo.ConfigParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelListenConfig", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelListenConfig indicates an expected call of CancelListenConfig
+func (mr *MockIConfigClientMockRecorder) CancelListenConfig(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelListenConfig", reflect.TypeOf((*MockIConfigClient)(nil).CancelListenConfig), params)
+}
+
+// CancelListener mocks base method
+func (m *MockIConfigClient) CancelListener(handle vo.ListenerHandle) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddConfigToListen", params)
+	ret := m.ctrl.Call(m, "CancelListener", handle)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AddConfigToListen indicates an expected call of AddConfigToListen
-func (mr *MockIConfigClientMockRecorder) AddConfigToListen(params interface{}) *gomock.Call {
+// CancelListener indicates an expected call of CancelListener
+func (mr *MockIConfigClientMockRecorder) CancelListener(handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelListener", reflect.TypeOf((*MockIConfigClient)(nil).CancelListener), handle)
+}
+
+// GetListenHealth mocks base method
+func (m *MockIConfigClient) GetListenHealth() []vo.ConfigListenHealth {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListenHealth")
+	ret0, _ := ret[0].([]vo.ConfigListenHealth)
+	return ret0
+}
+
+// GetListenHealth indicates an expected call of GetListenHealth
+func (mr *MockIConfigClientMockRecorder) GetListenHealth() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddConfigToListen", reflect.TypeOf((*MockIConfigClient)(nil).AddConfigToListen), params)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListenHealth", reflect.TypeOf((*MockIConfigClient)(nil).GetListenHealth))
 }
 
-// StopListenConfig mocks base method
-func (m *MockIConfigClient) StopListenConfig() {
+// FuzzyListenConfig mocks base method
+func (m *MockIConfigClient) FuzzyListenConfig(param vo.FuzzyListenConfigParam) (vo.ListenerHandle, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "StopListenConfig")
+	ret := m.ctrl.Call(m, "FuzzyListenConfig", param)
+	ret0, _ := ret[0].(vo.ListenerHandle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FuzzyListenConfig indicates an expected call of FuzzyListenConfig
+func (mr *MockIConfigClientMockRecorder) FuzzyListenConfig(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FuzzyListenConfig", reflect.TypeOf((*MockIConfigClient)(nil).FuzzyListenConfig), param)
+}
+
+// SearchConfig mocks base method
+func (m *MockIConfigClient) SearchConfig(param vo.SearchConfigParam) (*model.ConfigPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchConfig", param)
+	ret0, _ := ret[0].(*model.ConfigPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchConfig indicates an expected call of SearchConfig
+func (mr *MockIConfigClientMockRecorder) SearchConfig(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchConfig", reflect.TypeOf((*MockIConfigClient)(nil).SearchConfig), param)
+}
+
+// SearchConfigWithContext mocks base method
+func (m *MockIConfigClient) SearchConfigWithContext(ctx context.Context, param vo.SearchConfigParam) (*model.ConfigPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchConfigWithContext", ctx, param)
+	ret0, _ := ret[0].(*model.ConfigPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchConfigWithContext indicates an expected call of SearchConfigWithContext
+func (mr *MockIConfigClientMockRecorder) SearchConfigWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchConfigWithContext", reflect.TypeOf((*MockIConfigClient)(nil).SearchConfigWithContext), ctx, param)
+}
+
+// SearchConfigAll mocks base method
+func (m *MockIConfigClient) SearchConfigAll(param vo.SearchConfigAllParam) model.ConfigIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchConfigAll", param)
+	ret0, _ := ret[0].(model.ConfigIterator)
+	return ret0
+}
+
+// SearchConfigAll indicates an expected call of SearchConfigAll
+func (mr *MockIConfigClientMockRecorder) SearchConfigAll(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchConfigAll", reflect.TypeOf((*MockIConfigClient)(nil).SearchConfigAll), param)
+}
+
+// SearchConfigAllWithContext mocks base method
+func (m *MockIConfigClient) SearchConfigAllWithContext(ctx context.Context, param vo.SearchConfigAllParam) model.ConfigIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchConfigAllWithContext", ctx, param)
+	ret0, _ := ret[0].(model.ConfigIterator)
+	return ret0
+}
+
+// SearchConfigAllWithContext indicates an expected call of SearchConfigAllWithContext
+func (mr *MockIConfigClientMockRecorder) SearchConfigAllWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchConfigAllWithContext", reflect.TypeOf((*MockIConfigClient)(nil).SearchConfigAllWithContext), ctx, param)
 }
 
-// StopListenConfig indicates an expected call of StopListenConfig
-func (mr *MockIConfigClientMockRecorder) StopListenConfig() *gomock.Call {
+// ExportConfigs mocks base method
+func (m *MockIConfigClient) ExportConfigs(param vo.SearchConfigParam) (io.Reader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportConfigs", param)
+	ret0, _ := ret[0].(io.Reader)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportConfigs indicates an expected call of ExportConfigs
+func (mr *MockIConfigClientMockRecorder) ExportConfigs(param interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopListenConfig", reflect.TypeOf((*MockIConfigClient)(nil).StopListenConfig))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportConfigs", reflect.TypeOf((*MockIConfigClient)(nil).ExportConfigs), param)
 }
 
-// GetConfigContent mocks base method
-func (m *MockIConfigClient) GetConfigContent(dataId, groupId string) (string, error) {
+// ExportConfigsWithContext mocks base method
+func (m *MockIConfigClient) ExportConfigsWithContext(ctx context.Context, param vo.SearchConfigParam) (io.Reader, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetConfigContent", dataId, groupId)
+	ret := m.ctrl.Call(m, "ExportConfigsWithContext", ctx, param)
+	ret0, _ := ret[0].(io.Reader)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportConfigsWithContext indicates an expected call of ExportConfigsWithContext
+func (mr *MockIConfigClientMockRecorder) ExportConfigsWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportConfigsWithContext", reflect.TypeOf((*MockIConfigClient)(nil).ExportConfigsWithContext), ctx, param)
+}
+
+// ImportConfigs mocks base method
+func (m *MockIConfigClient) ImportConfigs(archive io.Reader, policy vo.ImportPolicy) (*model.ConfigImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportConfigs", archive, policy)
+	ret0, _ := ret[0].(*model.ConfigImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportConfigs indicates an expected call of ImportConfigs
+func (mr *MockIConfigClientMockRecorder) ImportConfigs(archive, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportConfigs", reflect.TypeOf((*MockIConfigClient)(nil).ImportConfigs), archive, policy)
+}
+
+// ImportConfigsWithContext mocks base method
+func (m *MockIConfigClient) ImportConfigsWithContext(ctx context.Context, archive io.Reader, policy vo.ImportPolicy) (*model.ConfigImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportConfigsWithContext", ctx, archive, policy)
+	ret0, _ := ret[0].(*model.ConfigImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportConfigsWithContext indicates an expected call of ImportConfigsWithContext
+func (mr *MockIConfigClientMockRecorder) ImportConfigsWithContext(ctx, archive, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportConfigsWithContext", reflect.TypeOf((*MockIConfigClient)(nil).ImportConfigsWithContext), ctx, archive, policy)
+}
+
+// PublishAggr mocks base method
+func (m *MockIConfigClient) PublishAggr(param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishAggr", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishAggr indicates an expected call of PublishAggr
+func (mr *MockIConfigClientMockRecorder) PublishAggr(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishAggr", reflect.TypeOf((*MockIConfigClient)(nil).PublishAggr), param)
+}
+
+// PublishAggrWithContext mocks base method
+func (m *MockIConfigClient) PublishAggrWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishAggrWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishAggrWithContext indicates an expected call of PublishAggrWithContext
+func (mr *MockIConfigClientMockRecorder) PublishAggrWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishAggrWithContext", reflect.TypeOf((*MockIConfigClient)(nil).PublishAggrWithContext), ctx, param)
+}
+
+// RemoveAggr mocks base method
+func (m *MockIConfigClient) RemoveAggr(param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAggr", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveAggr indicates an expected call of RemoveAggr
+func (mr *MockIConfigClientMockRecorder) RemoveAggr(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAggr", reflect.TypeOf((*MockIConfigClient)(nil).RemoveAggr), param)
+}
+
+// RemoveAggrWithContext mocks base method
+func (m *MockIConfigClient) RemoveAggrWithContext(ctx context.Context, param vo.ConfigParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAggrWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveAggrWithContext indicates an expected call of RemoveAggrWithContext
+func (mr *MockIConfigClientMockRecorder) RemoveAggrWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAggrWithContext", reflect.TypeOf((*MockIConfigClient)(nil).RemoveAggrWithContext), ctx, param)
+}
+
+// GetAggr mocks base method
+func (m *MockIConfigClient) GetAggr(param vo.ConfigParam) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAggr", param)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetConfigContent indicates an expected call of GetConfigContent
-func (mr *MockIConfigClientMockRecorder) GetConfigContent(dataId, groupId interface{}) *gomock.Call {
+// GetAggr indicates an expected call of GetAggr
+func (mr *MockIConfigClientMockRecorder) GetAggr(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAggr", reflect.TypeOf((*MockIConfigClient)(nil).GetAggr), param)
+}
+
+// GetAggrWithContext mocks base method
+func (m *MockIConfigClient) GetAggrWithContext(ctx context.Context, param vo.ConfigParam) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAggrWithContext", ctx, param)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAggrWithContext indicates an expected call of GetAggrWithContext
+func (mr *MockIConfigClientMockRecorder) GetAggrWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAggrWithContext", reflect.TypeOf((*MockIConfigClient)(nil).GetAggrWithContext), ctx, param)
+}
+
+// Ready mocks base method
+func (m *MockIConfigClient) Ready() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready
+func (mr *MockIConfigClientMockRecorder) Ready() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockIConfigClient)(nil).Ready))
+}
+
+// WaitReady mocks base method
+func (m *MockIConfigClient) WaitReady(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitReady", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitReady indicates an expected call of WaitReady
+func (mr *MockIConfigClientMockRecorder) WaitReady(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitReady", reflect.TypeOf((*MockIConfigClient)(nil).WaitReady), ctx)
+}
+
+// CloseClient mocks base method
+func (m *MockIConfigClient) CloseClient() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CloseClient")
+}
+
+// CloseClient indicates an expected call of CloseClient
+func (mr *MockIConfigClientMockRecorder) CloseClient() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigContent", reflect.TypeOf((*MockIConfigClient)(nil).GetConfigContent), dataId, groupId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseClient", reflect.TypeOf((*MockIConfigClient)(nil).CloseClient))
 }
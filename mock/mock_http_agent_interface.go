@@ -21,6 +21,7 @@
 package mock
 
 import (
+	context "context"
 	http "net/http"
 	reflect "reflect"
 
@@ -138,3 +139,18 @@ func (mr *MockIHttpAgentMockRecorder) Request(method, path, header, timeoutMs, p
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockIHttpAgent)(nil).Request), method, path, header, timeoutMs, params)
 }
+
+// RequestWithContext mocks base method
+func (m *MockIHttpAgent) RequestWithContext(ctx context.Context, method, path string, header http.Header, timeoutMs uint64, params map[string]string) (*http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestWithContext", ctx, method, path, header, timeoutMs, params)
+	ret0, _ := ret[0].(*http.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestWithContext indicates an expected call of RequestWithContext
+func (mr *MockIHttpAgentMockRecorder) RequestWithContext(ctx, method, path, header, timeoutMs, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestWithContext", reflect.TypeOf((*MockIHttpAgent)(nil).RequestWithContext), ctx, method, path, header, timeoutMs, params)
+}
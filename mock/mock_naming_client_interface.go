@@ -21,7 +21,9 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/yefengzhichen/nacos-sdk-go-v1x/model"
@@ -66,6 +68,21 @@ func (mr *MockINamingClientMockRecorder) RegisterInstance(param interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterInstance", reflect.TypeOf((*MockINamingClient)(nil).RegisterInstance), param)
 }
 
+// RegisterInstanceWithContext mocks base method
+func (m *MockINamingClient) RegisterInstanceWithContext(ctx context.Context, param vo.RegisterInstanceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterInstanceWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterInstanceWithContext indicates an expected call of RegisterInstanceWithContext
+func (mr *MockINamingClientMockRecorder) RegisterInstanceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterInstanceWithContext", reflect.TypeOf((*MockINamingClient)(nil).RegisterInstanceWithContext), ctx, param)
+}
+
 // DeregisterInstance mocks base method
 func (m *MockINamingClient) DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +98,51 @@ func (mr *MockINamingClientMockRecorder) DeregisterInstance(param interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterInstance", reflect.TypeOf((*MockINamingClient)(nil).DeregisterInstance), param)
 }
 
+// DeregisterInstanceWithContext mocks base method
+func (m *MockINamingClient) DeregisterInstanceWithContext(ctx context.Context, param vo.DeregisterInstanceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeregisterInstanceWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeregisterInstanceWithContext indicates an expected call of DeregisterInstanceWithContext
+func (mr *MockINamingClientMockRecorder) DeregisterInstanceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterInstanceWithContext", reflect.TypeOf((*MockINamingClient)(nil).DeregisterInstanceWithContext), ctx, param)
+}
+
+// UpdateInstance mocks base method
+func (m *MockINamingClient) UpdateInstance(param vo.UpdateInstanceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInstance", param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInstance indicates an expected call of UpdateInstance
+func (mr *MockINamingClientMockRecorder) UpdateInstance(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstance", reflect.TypeOf((*MockINamingClient)(nil).UpdateInstance), param)
+}
+
+// UpdateInstanceWithContext mocks base method
+func (m *MockINamingClient) UpdateInstanceWithContext(ctx context.Context, param vo.UpdateInstanceParam) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInstanceWithContext", ctx, param)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInstanceWithContext indicates an expected call of UpdateInstanceWithContext
+func (mr *MockINamingClientMockRecorder) UpdateInstanceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstanceWithContext", reflect.TypeOf((*MockINamingClient)(nil).UpdateInstanceWithContext), ctx, param)
+}
+
 // GetService mocks base method
 func (m *MockINamingClient) GetService(param vo.GetServiceParam) (model.Service, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +158,51 @@ func (mr *MockINamingClientMockRecorder) GetService(param interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetService", reflect.TypeOf((*MockINamingClient)(nil).GetService), param)
 }
 
+// GetServiceWithContext mocks base method
+func (m *MockINamingClient) GetServiceWithContext(ctx context.Context, param vo.GetServiceParam) (model.Service, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceWithContext", ctx, param)
+	ret0, _ := ret[0].(model.Service)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceWithContext indicates an expected call of GetServiceWithContext
+func (mr *MockINamingClientMockRecorder) GetServiceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceWithContext", reflect.TypeOf((*MockINamingClient)(nil).GetServiceWithContext), ctx, param)
+}
+
+// SelectAllInstances mocks base method
+func (m *MockINamingClient) SelectAllInstances(param vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAllInstances", param)
+	ret0, _ := ret[0].([]model.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAllInstances indicates an expected call of SelectAllInstances
+func (mr *MockINamingClientMockRecorder) SelectAllInstances(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAllInstances", reflect.TypeOf((*MockINamingClient)(nil).SelectAllInstances), param)
+}
+
+// SelectAllInstancesWithContext mocks base method
+func (m *MockINamingClient) SelectAllInstancesWithContext(ctx context.Context, param vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAllInstancesWithContext", ctx, param)
+	ret0, _ := ret[0].([]model.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAllInstancesWithContext indicates an expected call of SelectAllInstancesWithContext
+func (mr *MockINamingClientMockRecorder) SelectAllInstancesWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAllInstancesWithContext", reflect.TypeOf((*MockINamingClient)(nil).SelectAllInstancesWithContext), ctx, param)
+}
+
 // SelectInstances mocks base method
 func (m *MockINamingClient) SelectInstances(param vo.SelectInstancesParam) ([]model.Instance, error) {
 	m.ctrl.T.Helper()
@@ -111,6 +218,21 @@ func (mr *MockINamingClientMockRecorder) SelectInstances(param interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectInstances", reflect.TypeOf((*MockINamingClient)(nil).SelectInstances), param)
 }
 
+// SelectInstancesWithContext mocks base method
+func (m *MockINamingClient) SelectInstancesWithContext(ctx context.Context, param vo.SelectInstancesParam) ([]model.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectInstancesWithContext", ctx, param)
+	ret0, _ := ret[0].([]model.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectInstancesWithContext indicates an expected call of SelectInstancesWithContext
+func (mr *MockINamingClientMockRecorder) SelectInstancesWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectInstancesWithContext", reflect.TypeOf((*MockINamingClient)(nil).SelectInstancesWithContext), ctx, param)
+}
+
 // SelectOneHealthyInstance mocks base method
 func (m *MockINamingClient) SelectOneHealthyInstance(param vo.SelectOneHealthInstanceParam) (*model.Instance, error) {
 	m.ctrl.T.Helper()
@@ -126,6 +248,61 @@ func (mr *MockINamingClientMockRecorder) SelectOneHealthyInstance(param interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectOneHealthyInstance", reflect.TypeOf((*MockINamingClient)(nil).SelectOneHealthyInstance), param)
 }
 
+// SelectOneHealthyInstanceWithContext mocks base method
+func (m *MockINamingClient) SelectOneHealthyInstanceWithContext(ctx context.Context, param vo.SelectOneHealthInstanceParam) (*model.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectOneHealthyInstanceWithContext", ctx, param)
+	ret0, _ := ret[0].(*model.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectOneHealthyInstanceWithContext indicates an expected call of SelectOneHealthyInstanceWithContext
+func (mr *MockINamingClientMockRecorder) SelectOneHealthyInstanceWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectOneHealthyInstanceWithContext", reflect.TypeOf((*MockINamingClient)(nil).SelectOneHealthyInstanceWithContext), ctx, param)
+}
+
+// GetProtectStates mocks base method
+func (m *MockINamingClient) GetProtectStates() []model.ProtectState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProtectStates")
+	ret0, _ := ret[0].([]model.ProtectState)
+	return ret0
+}
+
+// GetProtectStates indicates an expected call of GetProtectStates
+func (mr *MockINamingClientMockRecorder) GetProtectStates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProtectStates", reflect.TypeOf((*MockINamingClient)(nil).GetProtectStates))
+}
+
+// ReportResult mocks base method
+func (m *MockINamingClient) ReportResult(instance model.Instance, err error, latency time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReportResult", instance, err, latency)
+}
+
+// ReportResult indicates an expected call of ReportResult
+func (mr *MockINamingClientMockRecorder) ReportResult(instance, err, latency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportResult", reflect.TypeOf((*MockINamingClient)(nil).ReportResult), instance, err, latency)
+}
+
+// GetOutlierStats mocks base method
+func (m *MockINamingClient) GetOutlierStats() []model.OutlierStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutlierStats")
+	ret0, _ := ret[0].([]model.OutlierStats)
+	return ret0
+}
+
+// GetOutlierStats indicates an expected call of GetOutlierStats
+func (mr *MockINamingClientMockRecorder) GetOutlierStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutlierStats", reflect.TypeOf((*MockINamingClient)(nil).GetOutlierStats))
+}
+
 // Subscribe mocks base method
 func (m *MockINamingClient) Subscribe(param *vo.SubscribeParam) error {
 	m.ctrl.T.Helper()
@@ -140,6 +317,20 @@ func (mr *MockINamingClientMockRecorder) Subscribe(param interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockINamingClient)(nil).Subscribe), param)
 }
 
+// SubscribeWithContext mocks base method
+func (m *MockINamingClient) SubscribeWithContext(ctx context.Context, param *vo.SubscribeParam) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeWithContext", ctx, param)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubscribeWithContext indicates an expected call of SubscribeWithContext
+func (mr *MockINamingClientMockRecorder) SubscribeWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeWithContext", reflect.TypeOf((*MockINamingClient)(nil).SubscribeWithContext), ctx, param)
+}
+
 // Unsubscribe mocks base method
 func (m *MockINamingClient) Unsubscribe(param *vo.SubscribeParam) error {
 	m.ctrl.T.Helper()
@@ -153,3 +344,103 @@ func (mr *MockINamingClientMockRecorder) Unsubscribe(param interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockINamingClient)(nil).Unsubscribe), param)
 }
+
+// GetAllServicesInfo mocks base method
+func (m *MockINamingClient) GetAllServicesInfo(param vo.GetAllServiceInfoParam) (model.ServiceList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllServicesInfo", param)
+	ret0, _ := ret[0].(model.ServiceList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllServicesInfo indicates an expected call of GetAllServicesInfo
+func (mr *MockINamingClientMockRecorder) GetAllServicesInfo(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllServicesInfo", reflect.TypeOf((*MockINamingClient)(nil).GetAllServicesInfo), param)
+}
+
+// GetAllServicesInfoWithContext mocks base method
+func (m *MockINamingClient) GetAllServicesInfoWithContext(ctx context.Context, param vo.GetAllServiceInfoParam) (model.ServiceList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllServicesInfoWithContext", ctx, param)
+	ret0, _ := ret[0].(model.ServiceList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllServicesInfoWithContext indicates an expected call of GetAllServicesInfoWithContext
+func (mr *MockINamingClientMockRecorder) GetAllServicesInfoWithContext(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllServicesInfoWithContext", reflect.TypeOf((*MockINamingClient)(nil).GetAllServicesInfoWithContext), ctx, param)
+}
+
+// GetCatalogServices mocks base method
+func (m *MockINamingClient) GetCatalogServices(namesSpace string) (model.CatalogServiceList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCatalogServices", namesSpace)
+	ret0, _ := ret[0].(model.CatalogServiceList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCatalogServices indicates an expected call of GetCatalogServices
+func (mr *MockINamingClientMockRecorder) GetCatalogServices(namesSpace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalogServices", reflect.TypeOf((*MockINamingClient)(nil).GetCatalogServices), namesSpace)
+}
+
+// GetCatalogServicesWithContext mocks base method
+func (m *MockINamingClient) GetCatalogServicesWithContext(ctx context.Context, namesSpace string) (model.CatalogServiceList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCatalogServicesWithContext", ctx, namesSpace)
+	ret0, _ := ret[0].(model.CatalogServiceList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCatalogServicesWithContext indicates an expected call of GetCatalogServicesWithContext
+func (mr *MockINamingClientMockRecorder) GetCatalogServicesWithContext(ctx, namesSpace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalogServicesWithContext", reflect.TypeOf((*MockINamingClient)(nil).GetCatalogServicesWithContext), ctx, namesSpace)
+}
+
+// Ready mocks base method
+func (m *MockINamingClient) Ready() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready
+func (mr *MockINamingClientMockRecorder) Ready() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockINamingClient)(nil).Ready))
+}
+
+// WaitReady mocks base method
+func (m *MockINamingClient) WaitReady(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitReady", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitReady indicates an expected call of WaitReady
+func (mr *MockINamingClientMockRecorder) WaitReady(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitReady", reflect.TypeOf((*MockINamingClient)(nil).WaitReady), ctx)
+}
+
+// CloseClient mocks base method
+func (m *MockINamingClient) CloseClient() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CloseClient")
+}
+
+// CloseClient indicates an expected call of CloseClient
+func (mr *MockINamingClientMockRecorder) CloseClient() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseClient", reflect.TypeOf((*MockINamingClient)(nil).CloseClient))
+}
@@ -16,6 +16,14 @@
 
 package model
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type ConfigItem struct {
 	Id      string `param:"id"`
 	DataId  string `param:"dataId"`
@@ -25,9 +33,201 @@ type ConfigItem struct {
 	Tenant  string `param:"tenant"`
 	Appname string `param:"appname"`
 }
+type ConfigDetail struct {
+	Id               string `param:"id"`
+	DataId           string `param:"dataId"`
+	Group            string `param:"group"`
+	Content          string `param:"content"`
+	Md5              string `param:"md5"`
+	Tenant           string `param:"tenant"`
+	AppName          string `param:"appName"`
+	Type             string `param:"type"`
+	Desc             string `param:"desc"`
+	ConfigTags       string `param:"configTags"`
+	Effect           string `param:"effect"`
+	Schema           string `param:"schema"`
+	Use              string `param:"use"`
+	CreateUser       string `param:"createUser"`
+	CreateIp         string `param:"createIp"`
+	CreateTime       int64  `param:"createTime"` // milliseconds
+	ModifyTime       int64  `param:"modifyTime"` // milliseconds
+	EncryptedDataKey string `param:"encryptedDataKey"`
+}
+
+type ConfigBeta struct {
+	Id               string `param:"id"`
+	DataId           string `param:"dataId"`
+	Group            string `param:"group"`
+	Tenant           string `param:"tenant"`
+	AppName          string `param:"appName"`
+	Content          string `param:"content"`
+	Md5              string `param:"md5"`
+	BetaIps          string `param:"betaIps"` // the client ips joined by ','
+	EncryptedDataKey string `param:"encryptedDataKey"`
+}
+
 type ConfigPage struct {
 	TotalCount     int          `param:"totalCount"`
 	PageNumber     int          `param:"pageNumber"`
 	PagesAvailable int          `param:"pagesAvailable"`
 	PageItems      []ConfigItem `param:"pageItems"`
 }
+
+// ConfigIterator iterates over the configs of all the search pages, the usage is like bufio.Scanner:
+//
+//	it := client.SearchConfigAll(param)
+//	defer it.Close()
+//	for it.Next() {
+//		item := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type ConfigIterator interface {
+	// Next advances to the next config, it returns false when all the pages are iterated, an error
+	// occurs or the iterator is closed
+	Next() bool
+
+	// Item returns the current config
+	Item() ConfigItem
+
+	// Err returns the error which stops the iteration
+	Err() error
+
+	// Close stops fetching the remaining pages, it can be called to stop early
+	Close()
+}
+
+type ConfigOpType string
+
+const (
+	CONFIG_OP_INSERT ConfigOpType = "I"
+	CONFIG_OP_UPDATE ConfigOpType = "U"
+	CONFIG_OP_DELETE ConfigOpType = "D"
+)
+
+// ConfigHistory is a change record of config, the content is the config before an update or delete
+// and the config inserted for an insert
+type ConfigHistory struct {
+	Id               int64
+	LastId           int64
+	DataId           string
+	Group            string
+	Tenant           string
+	AppName          string
+	Md5              string
+	Content          string
+	SrcIp            string // the ip of the operator
+	SrcUser          string // the operator
+	OpType           ConfigOpType
+	CreatedTime      time.Time
+	LastModifiedTime time.Time
+}
+
+type ConfigHistoryPage struct {
+	TotalCount     int             `param:"totalCount"`
+	PageNumber     int             `param:"pageNumber"`
+	PagesAvailable int             `param:"pagesAvailable"`
+	PageItems      []ConfigHistory `param:"pageItems"`
+}
+
+type configHistoryJson struct {
+	Id               json.RawMessage `json:"id"`
+	LastId           json.RawMessage `json:"lastId"`
+	DataId           string          `json:"dataId"`
+	Group            string          `json:"group"`
+	Tenant           string          `json:"tenant"`
+	AppName          string          `json:"appName"`
+	Md5              string          `json:"md5"`
+	Content          string          `json:"content"`
+	SrcIp            string          `json:"srcIp"`
+	SrcUser          string          `json:"srcUser"`
+	OpType           string          `json:"opType"`
+	CreatedTime      json.RawMessage `json:"createdTime"`
+	LastModifiedTime json.RawMessage `json:"lastModifiedTime"`
+}
+
+// UnmarshalJSON accepts the ids as numbers or strings and the times as milliseconds or formatted strings,
+// which depend on the version of nacos server
+func (history *ConfigHistory) UnmarshalJSON(data []byte) (err error) {
+	var historyJson configHistoryJson
+	if err = json.Unmarshal(data, &historyJson); err != nil {
+		return
+	}
+	*history = ConfigHistory{
+		DataId:  historyJson.DataId,
+		Group:   historyJson.Group,
+		Tenant:  historyJson.Tenant,
+		AppName: historyJson.AppName,
+		Md5:     historyJson.Md5,
+		Content: historyJson.Content,
+		SrcIp:   historyJson.SrcIp,
+		SrcUser: historyJson.SrcUser,
+		OpType:  ConfigOpType(strings.TrimSpace(historyJson.OpType)),
+	}
+	if history.Id, err = parseJsonInt(historyJson.Id); err != nil {
+		return
+	}
+	if history.LastId, err = parseJsonInt(historyJson.LastId); err != nil {
+		return
+	}
+	if history.CreatedTime, err = parseJsonTime(historyJson.CreatedTime); err != nil {
+		return
+	}
+	history.LastModifiedTime, err = parseJsonTime(historyJson.LastModifiedTime)
+	return
+}
+
+func parseJsonInt(data json.RawMessage) (int64, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return 0, nil
+	}
+	return strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+}
+
+var historyTimeLayouts = []string{
+	"2006-01-02T15:04:05.000-0700",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+}
+
+func parseJsonTime(data json.RawMessage) (time.Time, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return time.Time{}, nil
+	}
+	if millis, err := strconv.ParseInt(string(data), 10, 64); err == nil {
+		return time.Unix(0, millis*int64(time.Millisecond)), nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return time.Time{}, err
+	}
+	for _, layout := range historyTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time:%s", value)
+}
+
+type ConfigImportStatus string
+
+const (
+	CONFIG_IMPORT_SUCCESS ConfigImportStatus = "SUCCESS"
+	CONFIG_IMPORT_SKIPPED ConfigImportStatus = "SKIPPED"
+	CONFIG_IMPORT_FAILED  ConfigImportStatus = "FAILED"
+	CONFIG_IMPORT_ABORTED ConfigImportStatus = "ABORTED"
+)
+
+type ConfigImportItem struct {
+	DataId  string
+	Group   string
+	Status  ConfigImportStatus
+	Message string // the reason of FAILED, SKIPPED and ABORTED
+}
+
+type ConfigImportResult struct {
+	SuccessCount int
+	SkipCount    int
+	FailCount    int // the count of FAILED and ABORTED
+	Items        []ConfigImportItem
+}
@@ -134,3 +134,34 @@ type CatalogServiceList struct {
 	Count       string            `json:"count"`
 	ServiceList []*CatalogService `json:"serviceList"`
 }
+
+// ProtectState is the protect threshold state of a service, see ClientConfig.UseProtectThreshold
+type ProtectState struct {
+	ServiceName      string // the grouped name of the service
+	Clusters         string
+	ProtectThreshold float64
+	// HealthyRatio is the count of the healthy instances over the count of all the instances
+	HealthyRatio float64
+	// Protected is true when HealthyRatio is not above ProtectThreshold, then the unhealthy instances are selected too
+	Protected bool
+}
+
+// ProtectListener is called when a service enters or leaves the protection mode
+type ProtectListener func(state ProtectState)
+
+// OutlierStats is the outlier detection state of an instance reported by NamingClient.ReportResult
+type OutlierStats struct {
+	ServiceName string // the grouped name of the service
+	Ip          string
+	Port        uint64
+	// Successes, Failures and AvgLatency are of the results in the current interval of the error rate
+	Successes           int
+	Failures            int
+	AvgLatency          time.Duration
+	ConsecutiveFailures int
+	// Ejected is true when the instance is skipped by the selection until EjectedUntil
+	Ejected      bool
+	EjectedUntil time.Time
+	// EjectionCount is the count of the recent ejections, which doubles the next ejection time
+	EjectionCount int
+}
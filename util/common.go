@@ -20,12 +20,15 @@ import (
 	"encoding/json"
 	"net"
 	"net/url"
+	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/constant"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/logger"
 	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/vo"
 )
 
 func CurrentMillis() int64 {
@@ -65,6 +68,26 @@ func GetConfigCacheKey(dataId string, group string, tenant string) string {
 	return dataId + constant.CONFIG_INFO_SPLITER + group + constant.CONFIG_INFO_SPLITER + tenant
 }
 
+// GetConfigType returns configType if it is not empty, otherwise the type is guessed by the extension of dataId
+func GetConfigType(dataId string, configType vo.ConfigType) vo.ConfigType {
+	if len(configType) > 0 {
+		return configType
+	}
+	switch strings.ToLower(path.Ext(dataId)) {
+	case ".json":
+		return vo.JSON
+	case ".yaml", ".yml":
+		return vo.YAML
+	case ".xml":
+		return vo.XML
+	case ".properties":
+		return vo.PROPERTIES
+	case ".html", ".htm":
+		return vo.HTML
+	}
+	return vo.TEXT
+}
+
 var (
 	localIP     = ""
 	privateCIDR []*net.IPNet
@@ -0,0 +1,128 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+)
+
+const propertyWhitespace = " \t\f"
+
+// ParseProperties parses the content of java properties format into a map,
+// the comment lines start with '#' or '!', the key ends at the first unescaped '=', ':' or whitespace,
+// and a line ended with an odd number of '\' is continued by the next line.
+// Unlike java, the trailing whitespace of the value is trimmed unless it's escaped
+func ParseProperties(content string) (map[string]string, error) {
+	properties := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), len(content)+1)
+	var logicalLine string
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), propertyWhitespace)
+		if len(logicalLine) == 0 && (len(line) == 0 || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+		if trailingBackslashes(line)%2 == 1 {
+			logicalLine += line[:len(line)-1]
+			continue
+		}
+		logicalLine += line
+		key, value := splitProperty(logicalLine)
+		properties[key] = value
+		logicalLine = ""
+	}
+	if len(logicalLine) > 0 {
+		key, value := splitProperty(logicalLine)
+		properties[key] = value
+	}
+	return properties, scanner.Err()
+}
+
+// splitProperty splits the logical line by the first unescaped '=', ':' or whitespace,
+// the whitespace around the separator is skipped
+func splitProperty(line string) (key, value string) {
+	end := len(line)
+	escaped := false
+	for i := 0; i < len(line) && end == len(line); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case line[i] == '\\':
+			escaped = true
+		case line[i] == '=' || line[i] == ':' || strings.IndexByte(propertyWhitespace, line[i]) >= 0:
+			end = i
+		}
+	}
+	value = strings.TrimLeft(line[end:], propertyWhitespace)
+	if len(value) > 0 && (value[0] == '=' || value[0] == ':') {
+		value = strings.TrimLeft(value[1:], propertyWhitespace)
+	}
+	trimmed := strings.TrimRight(value, propertyWhitespace)
+	if trailingBackslashes(trimmed)%2 == 1 {
+		// keep the escaped whitespace
+		trimmed = value[:len(trimmed)+1]
+	}
+	return unescapeProperty(line[:end]), unescapeProperty(trimmed)
+}
+
+func trailingBackslashes(s string) int {
+	count := 0
+	for i := len(s) - 1; i >= 0 && s[i] == '\\'; i-- {
+		count++
+	}
+	return count
+}
+
+func unescapeProperty(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var builder strings.Builder
+	escaped := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !escaped && c == '\\' {
+			escaped = true
+			continue
+		}
+		if escaped {
+			escaped = false
+			switch c {
+			case 'n':
+				c = '\n'
+			case 't':
+				c = '\t'
+			case 'r':
+				c = '\r'
+			case 'f':
+				c = '\f'
+			case 'u':
+				if i+4 < len(s) {
+					if code, err := strconv.ParseUint(s[i+1:i+5], 16, 16); err == nil {
+						builder.WriteRune(rune(code))
+						i += 4
+						continue
+					}
+				}
+			}
+		}
+		builder.WriteByte(c)
+	}
+	return builder.String()
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProperties(t *testing.T) {
+	content := "# comment\n" +
+		"! another comment\n" +
+		"server.port=8080\n" +
+		"  server.name : demo \n" +
+		"\n" +
+		"server.hosts=a,\\\n" +
+		"    b\n" +
+		"path=c\\:\\\\tmp\n" +
+		"empty"
+	properties, err := ParseProperties(content)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"server.port":  "8080",
+		"server.name":  "demo",
+		"server.hosts": "a,b",
+		"path":         "c:\\tmp",
+		"empty":        "",
+	}, properties)
+}
+
+func TestParsePropertiesSeparators(t *testing.T) {
+	content := "a\\=b=c\n" +
+		"key value\n" +
+		"tab\tkey = with space\n" +
+		"colon\\:key:v\n" +
+		"space\\ key=v\n" +
+		"escaped.end=v\\ \n" +
+		"even.backslashes=c\\\\\n" +
+		"next=line\n" +
+		"odd.backslashes=c\\\\\\\n" +
+		"  d\n" +
+		"unicode=\\u4e2d\\u6587"
+	properties, err := ParseProperties(content)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"a=b":              "c",
+		"key":              "value",
+		"tab":              "key = with space",
+		"colon:key":        "v",
+		"space key":        "v",
+		"escaped.end":      "v ",
+		"even.backslashes": "c\\",
+		"next":             "line",
+		"odd.backslashes":  "c\\d",
+		"unicode":          "中文",
+	}, properties)
+	_, ok := properties["a\\"]
+	assert.False(t, ok)
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 1999-2020 Alibaba Group Holding Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WarmUp loads the items in parallel when the client is created, each item is retried after retryDelay
+// until it is loaded or ctx is done
+type WarmUp struct {
+	remaining int32
+	ready     chan struct{}
+}
+
+func NewWarmUp(ctx context.Context, wg *sync.WaitGroup, count int, retryDelay time.Duration, load func(ctx context.Context, index int) error) *WarmUp {
+	w := &WarmUp{remaining: int32(count), ready: make(chan struct{})}
+	if count == 0 {
+		close(w.ready)
+		return w
+	}
+	wg.Add(count)
+	for i := 0; i < count; i++ {
+		go func(index int) {
+			defer wg.Done()
+			for load(ctx, index) != nil {
+				select {
+				case <-time.After(retryDelay):
+				case <-ctx.Done():
+					return
+				}
+			}
+			if atomic.AddInt32(&w.remaining, -1) == 0 {
+				close(w.ready)
+			}
+		}(i)
+	}
+	return w
+}
+
+// Ready returns true if all the items are loaded
+func (w *WarmUp) Ready() bool {
+	select {
+	case <-w.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// WaitReady waits until all the items are loaded, ctx.Err() is returned if ctx is done before that
+func (w *WarmUp) WaitReady(ctx context.Context) error {
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -16,6 +16,8 @@
 
 package vo
 
+import "time"
+
 type ConfigType string
 
 const (
@@ -29,6 +31,80 @@ const (
 
 type Listener func(namespace, group, dataId, data string)
 
+// ListenerHandle identifies a single listener registered by ListenConfig
+type ListenerHandle uint64
+
+type ConfigChangeType string
+
+const (
+	ADDED    ConfigChangeType = "added"
+	MODIFIED ConfigChangeType = "modified"
+	DELETED  ConfigChangeType = "deleted"
+)
+
+// ConfigSource is where the config content is read from
+type ConfigSource string
+
+const (
+	SOURCE_SERVER ConfigSource = "server"
+	// SOURCE_FAILOVER is the file in the failover directory, which takes priority over the server
+	SOURCE_FAILOVER ConfigSource = "failover"
+	// SOURCE_SNAPSHOT is the file in the snapshot directory, which is only used when the server is unavailable
+	SOURCE_SNAPSHOT ConfigSource = "snapshot"
+	// SOURCE_MEMORY is the content of the warm-up config kept in memory, which is updated by the long polling
+	SOURCE_MEMORY ConfigSource = "memory"
+)
+
+// ConfigChangeEvent describes a change of the listened config
+type ConfigChangeEvent struct {
+	Namespace  string
+	Group      string
+	DataId     string
+	OldContent string
+	NewContent string
+	OldMd5     string
+	NewMd5     string
+	ChangeType ConfigChangeType
+	// IsBeta is true if NewContent is the beta content published to this client by PublishConfigBeta
+	IsBeta bool
+	// Diff is the key level diff, it is nil unless the content is json, yaml or properties
+	Diff *ConfigDiff
+}
+
+// ConfigDiff is the key level diff of two config contents, the nested keys are joined by '.'
+type ConfigDiff struct {
+	Added    []ConfigKeyChange
+	Removed  []ConfigKeyChange
+	Modified []ConfigKeyChange
+}
+
+type ConfigKeyChange struct {
+	Key      string
+	OldValue string
+	NewValue string
+}
+
+type ChangeEventListener func(event ConfigChangeEvent)
+
+// ConfigListenHealth is the health of the long polling of a listened config
+type ConfigListenHealth struct {
+	Namespace string
+	Group     string
+	DataId    string
+	// LastCheckTime is the last time the long polling succeeded, it's zero before the first success
+	LastCheckTime time.Time
+	// ConsecutiveFailures is the count of the long polling failures since the last success
+	ConsecutiveFailures int
+	// LastError is the error of the last long polling, it's nil after a success
+	LastError error
+	// Degraded is true when ConsecutiveFailures reaches ClientConfig.ListenMaxFailures,
+	// the content of the listener may be stale
+	Degraded bool
+}
+
+// ListenHealthListener is called when the listened config becomes degraded or recovers
+type ListenHealthListener func(health ConfigListenHealth)
+
 type ConfigParam struct {
 	DataId  string     `param:"dataId"`  //required
 	Group   string     `param:"group"`   //required
@@ -36,7 +112,33 @@ type ConfigParam struct {
 	DatumId string     `param:"datumId"`
 	Type    ConfigType `param:"type"`
 
+	Desc       string `param:"desc"`
+	ConfigTags string `param:"config_tags"` // tags separated by ','
+	AppName    string `param:"appName"`
+	Effect     string `param:"effect"`
+	Schema     string `param:"schema"`
+
+	// OnChange is called when config change, the changes of a listener are delivered one by one in order
+	OnChange func(namespace, group, dataId, data string)
+	// OnChangeEvent is called with the old and new content when config change, it can be used with or instead of OnChange
+	OnChangeEvent ChangeEventListener
+	// OnListenHealthChange is called when the long polling of the config keeps failing and when it recovers
+	OnListenHealthChange ListenHealthListener
+}
+
+// FuzzyListenConfigParam listens all the configs whose dataId and group match the patterns,
+// '*' in the pattern matches any characters as the blur search of nacos
+type FuzzyListenConfigParam struct {
+	DataIdPattern string
+	GroupPattern  string
+	Type          ConfigType
+	// IntervalMs is the interval to search the matching configs, default is 30000
+	IntervalMs uint64
+
+	// OnChange is called with empty data when a matching config is deleted
 	OnChange func(namespace, group, dataId, data string)
+	// OnChangeEvent is called with ADDED when a matching config appears and DELETED when it is deleted
+	OnChangeEvent ChangeEventListener
 }
 
 type SearchConfigParam struct {
@@ -48,3 +150,26 @@ type SearchConfigParam struct {
 	PageNo   int    `param:"pageNo"`
 	PageSize int    `param:"pageSize"`
 }
+
+// SearchConfigAllParam is the param of SearchConfigAll, PageNo is ignored and PageSize is the size of each request
+type SearchConfigAllParam struct {
+	SearchConfigParam
+	Concurrency int // the max count of the pages fetched at a time, default is 1
+}
+
+type PageParam struct {
+	PageNo   int `param:"pageNo"`   // default is 1
+	PageSize int `param:"pageSize"` // default is 10
+}
+
+// ImportPolicy decides what ImportConfigs does with the configs which already exist on server
+type ImportPolicy string
+
+const (
+	// ABORT imports nothing if any config of the archive already exists
+	ABORT ImportPolicy = "ABORT"
+	// SKIP keeps the existing configs and imports the others
+	SKIP ImportPolicy = "SKIP"
+	// OVERWRITE replaces the existing configs
+	OVERWRITE ImportPolicy = "OVERWRITE"
+)
@@ -16,7 +16,10 @@
 
 package vo
 
-import "github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+import (
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/common/balancer"
+	"github.com/yefengzhichen/nacos-sdk-go-v1x/model"
+)
 
 type RegisterInstanceParam struct {
 	Ip          string            `param:"ip"`          //required
@@ -70,6 +73,8 @@ type SubscribeParam struct {
 	Clusters          []string                                           `param:"clusters"`    //optional,default:DEFAULT
 	GroupName         string                                             `param:"groupName"`   //optional,default:DEFAULT_GROUP
 	SubscribeCallback func(services []model.SubscribeService, err error) //required
+	Selector          string                                             //optional,metadata selector such as "version=v2,zone in (a,b),!canary"
+	SelectorFallback  bool                                               //optional,call back with all the instances when none matches Selector
 }
 
 type SelectAllInstancesParam struct {
@@ -83,10 +88,18 @@ type SelectInstancesParam struct {
 	ServiceName string   `param:"serviceName"` //required
 	GroupName   string   `param:"groupName"`   //optional,default:DEFAULT_GROUP
 	HealthyOnly bool     `param:"healthyOnly"` //optional,return only healthy instance
+	Selector    string   //optional,metadata selector such as "version=v2,zone in (a,b),!canary"
+	// SelectorFallback is optional, return the instances ignoring Selector when none of them matches it
+	SelectorFallback bool
 }
 
 type SelectOneHealthInstanceParam struct {
-	Clusters    []string `param:"clusters"`    //optional,default:DEFAULT
-	ServiceName string   `param:"serviceName"` //required
-	GroupName   string   `param:"groupName"`   //optional,default:DEFAULT_GROUP
+	Clusters    []string          `param:"clusters"`    //optional,default:DEFAULT
+	ServiceName string            `param:"serviceName"` //required
+	GroupName   string            `param:"groupName"`   //optional,default:DEFAULT_GROUP
+	Balancer    balancer.Balancer //optional,default:ClientConfig.Balancer
+	HashKey     string            //optional,the key of the caller for balancer.NewConsistentHash
+	Selector    string            //optional,metadata selector such as "version=v2,zone in (a,b),!canary"
+	// SelectorFallback is optional, select from the healthy instances ignoring Selector when none of them matches it
+	SelectorFallback bool
 }